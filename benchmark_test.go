@@ -0,0 +1,85 @@
+package weberr
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+var benchErr error
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = NotFound.Errorf("order %d not found", 42)
+	}
+}
+
+func BenchmarkErrorfNoStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = NotFound.ErrorfNoStack("order %d not found", 42)
+	}
+}
+
+func BenchmarkWrapf(b *testing.B) {
+	err := NotFound.Errorf("order not found")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchErr = Wrapf(err, "loading order")
+	}
+}
+
+// BenchmarkWrapfChain is the common pattern of wrapping at every level
+func BenchmarkWrapfChain(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = Wrapf(Wrapf(Wrapf(io.EOF, "reading order"), "loading cart"), "handling checkout")
+	}
+}
+
+func BenchmarkUserWrapf(b *testing.B) {
+	err := NotFound.Errorf("order not found")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchErr = UserWrapf(err, "Order not found")
+	}
+}
+
+func BenchmarkAttributes(b *testing.B) {
+	err := NotFound.Errorf("order not found")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchErr = WithSeverity(SetCode(AddDetails(err, "detail"), "orders.NOT_FOUND"), SeverityWarning)
+	}
+}
+
+func BenchmarkGetters(b *testing.B) {
+	err := Wrapf(SetCode(NotFound.UserWrapf(io.EOF, "Order not found"), "orders.NOT_FOUND"), "loading order")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = GetType(err)
+		_ = GetUserMessage(err)
+		_ = GetCode(err)
+		_ = GetDetails(err)
+	}
+}
+
+func BenchmarkWriteError(b *testing.B) {
+	err := Wrapf(SetCode(NotFound.UserWrapf(io.EOF, "Order not found"), "orders.NOT_FOUND"), "loading order")
+	r := httptest.NewRequest("GET", "/orders/42", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WriteError(httptest.NewRecorder(), r, err)
+	}
+}
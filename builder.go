@@ -0,0 +1,108 @@
+package weberr
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Builder composes an error one attribute at a time, as an alternative to
+// the combinations of Errorf, UserErrorf, Wrapf, UserWrapf and Set:
+//
+//	err := weberr.New("cluster lookup failed").
+//		Type(weberr.NotFound).
+//		User("Cluster not found").
+//		Code("CLUSTER_404").
+//		Detail("cluster_id", id).
+//		Err()
+//
+// Attributes that aren't set are inherited from the wrapped error, if any.
+type Builder struct {
+	msg         string
+	cause       error
+	errorType   ErrorType
+	userMessage *string
+	code        *Code
+	details     map[string]interface{}
+}
+
+// New starts building an error with an internal message.
+func New(msg string) *Builder {
+	return &Builder{msg: msg}
+}
+
+// Wrap makes the error wrap err, as Wrapf does.
+func (b *Builder) Wrap(err error) *Builder {
+	b.cause = err
+	return b
+}
+
+// Type sets the error type.
+func (b *Builder) Type(errorType ErrorType) *Builder {
+	b.errorType = errorType
+	return b
+}
+
+// User sets the formatted user message.
+func (b *Builder) User(msg string, args ...interface{}) *Builder {
+	userMessage := fmt.Sprintf(msg, args...)
+	b.userMessage = &userMessage
+	return b
+}
+
+// Code sets the error code.
+func (b *Builder) Code(code Code) *Builder {
+	b.code = &code
+	return b
+}
+
+// Detail adds a named detail. All named details are added to the error
+// as a single map[string]interface{} element.
+func (b *Builder) Detail(key string, value interface{}) *Builder {
+	if b.details == nil {
+		b.details = map[string]interface{}{}
+	}
+	b.details[key] = value
+	return b
+}
+
+// Err returns the built error.
+func (b *Builder) Err() error {
+	c := new(Error)
+
+	if b.cause != nil {
+		c.copyAttributes(b.cause)
+	}
+
+	if b.errorType != NoType {
+		c.errorType = b.errorType
+	}
+
+	switch {
+	case b.cause != nil && needsStack(c.errorType, b.cause):
+		c.error = errors.Wrap(b.cause, b.msg)
+	case b.cause != nil:
+		c.error = errors.WithMessage(b.cause, b.msg)
+	case captureStack(c.errorType):
+		c.error = errors.WithStack(plainErrorf("%s", b.msg))
+	default:
+		c.error = plainErrorf("%s", b.msg)
+	}
+	if b.cause != nil && captureStack(c.errorType) {
+		c.wrapped = &wrapSite{message: b.msg}
+		c.wrapped.record()
+	}
+	if b.userMessage != nil {
+		c.userMessage = *b.userMessage
+		c.messageKey, c.messageArgs = "", nil
+		c.messageTemplate, c.templateArgs = "", nil
+	}
+	if b.code != nil {
+		c.code = *b.code
+	}
+	if b.details != nil {
+		c.details = append(c.details, b.details)
+	}
+
+	return c
+}
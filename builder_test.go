@@ -0,0 +1,55 @@
+package weberr
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Builder logic tested:
+// Every attribute can be set independently
+// Unset attributes are inherited from the wrapped error
+// Named details are grouped in a single map
+func TestBuilder(t *testing.T) {
+	err := New("lookup failed").Type(NotFound).User("Cluster %s not found", "c1").Code("CLUSTER_404").
+		Detail("cluster_id", "c1").Detail("region", "eu").Err()
+
+	if err.Error() != "lookup failed" || GetType(err) != NotFound || GetUserMessage(err) != "Cluster c1 not found" ||
+		GetCode(err) != "CLUSTER_404" {
+		t.Errorf("got: %q %v %q %q", err, GetType(err), GetUserMessage(err), GetCode(err))
+	}
+	expected := []interface{}{map[string]interface{}{"cluster_id": "c1", "region": "eu"}}
+	if !reflect.DeepEqual(GetDetails(err), expected) {
+		t.Errorf("got: %v, want %v", GetDetails(err), expected)
+	}
+	// The trace starts at the first frame outside the package
+	if trace := GetStackTrace(err); !strings.HasPrefix(trace, "\ntesting.tRunner\n") {
+		t.Errorf("expected the caller of the test in the trace, got: %s", trace)
+	}
+
+	cause := AddDetails(SetCode(Conflict.UserErrorf("Already exists"), "DUP"), "foo")
+
+	tests := []struct {
+		err      error
+		expected string
+		errType  ErrorType
+		userMsg  string
+		code     Code
+		details  int
+	}{
+		{New("msg").Err(), "msg", NoType, "", "", 0},
+		{New("msg").Wrap(io.EOF).Err(), "msg: EOF", NoType, "", "", 0},
+		{New("msg").Wrap(cause).Err(), "msg: Already exists", Conflict, "Already exists", "DUP", 1},
+		{New("msg").Wrap(cause).Type(Gone).User("Gone").Code("GONE").Detail("a", 1).Err(), "msg: Already exists", Gone, "Gone", "GONE", 2},
+	}
+	for _, tt := range tests {
+		err := tt.err
+		if err.Error() != tt.expected || GetType(err) != tt.errType || GetUserMessage(err) != tt.userMsg ||
+			GetCode(err) != tt.code || len(GetDetails(err)) != tt.details {
+			t.Errorf("got: %q %v %q %q %d, want %q %v %q %q %d",
+				err, GetType(err), GetUserMessage(err), GetCode(err), len(GetDetails(err)),
+				tt.expected, tt.errType, tt.userMsg, tt.code, tt.details)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package weberr
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CatalogEntry describes an error code the application may return.
+type CatalogEntry struct {
+	Code Code
+	Type ErrorType
+	// Message is the default user message, as a format string.
+	Message string
+	// Translations maps a locale (e.g. "fr-CA") to a translated Message.
+	Translations map[string]string
+	// Description explains when the error happens, for the developers of
+	// the clients. It isn't sent in responses.
+	Description string
+	// Fields maps the names of the fields of the map detail errors with
+	// this code carry to their JSON type (e.g. "string"), for the clients
+	// that read them.
+	Fields map[string]string
+}
+
+// Catalog holds the error codes of an application and their user messages.
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	locales       []string
+	entries       []CatalogEntry
+}
+
+// NewCatalog creates a catalog whose messages are written in defaultLocale,
+// with translations for the other supported locales.
+func NewCatalog(defaultLocale string, locales ...string) *Catalog {
+	return &Catalog{
+		defaultLocale: defaultLocale,
+		locales:       locales,
+	}
+}
+
+// DefaultCatalog is the catalog used by the package-level functions.
+var DefaultCatalog = NewCatalog("en")
+
+// DefaultLocale returns the locale of the default messages.
+func (c *Catalog) DefaultLocale() string { return c.defaultLocale }
+
+// Locales returns the supported locales, the default locale first.
+func (c *Catalog) Locales() []string {
+	return append([]string{c.defaultLocale}, c.locales...)
+}
+
+// Add adds entries to the catalog.
+// Problems are not reported here, but by Validate.
+func (c *Catalog) Add(entries ...CatalogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = append(c.entries, entries...)
+}
+
+// Entries returns the entries of the catalog, in the order they were added.
+func (c *Catalog) Entries() []CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]CatalogEntry(nil), c.entries...)
+}
+
+// Entry returns the first entry added for code.
+func (c *Catalog) Entry(code Code) (CatalogEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, entry := range c.entries {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+
+	return CatalogEntry{}, false
+}
+
+// CatalogError lists the problems found while validating a catalog.
+type CatalogError struct {
+	Problems []string
+}
+
+func (e *CatalogError) Error() string {
+	return "invalid error catalog: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the catalog for duplicate codes, types without a status
+// mapping, translations whose placeholders don't match the default message,
+// and translations for locales the catalog doesn't support.
+// It returns a *CatalogError, or nil if the catalog is valid.
+func (c *Catalog) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var problems []string
+	seen := map[Code]bool{}
+
+	for _, entry := range c.entries {
+		if entry.Code == "" {
+			problems = append(problems, fmt.Sprintf("entry with message %q has no code", entry.Message))
+		} else if seen[entry.Code] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate code", entry.Code))
+		}
+		seen[entry.Code] = true
+
+		status := entry.Type.HTTPStatus()
+		if status < 400 || status > 599 || http.StatusText(status) == "" {
+			problems = append(problems, fmt.Sprintf("%s: type %d has no error status mapping", entry.Code, entry.Type))
+		}
+
+		verbs := formatVerbs(entry.Message)
+		for _, locale := range sortedKeys(entry.Translations) {
+			if !c.supports(locale) {
+				problems = append(problems, fmt.Sprintf("%s: locale %q is not supported by the catalog", entry.Code, locale))
+			}
+			if formatVerbs(entry.Translations[locale]) != verbs {
+				problems = append(problems, fmt.Sprintf("%s: %q translation placeholders don't match the default message", entry.Code, locale))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CatalogError{Problems: problems}
+	}
+
+	return nil
+}
+
+// supports reports whether locale is one of the catalog locales
+func (c *Catalog) supports(locale string) bool {
+	for _, l := range c.Locales() {
+		if strings.EqualFold(l, locale) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatVerbs returns the sorted verbs of a format string, e.g. "ds" for
+// "%s has %d items", so that reordered translations still match.
+func formatVerbs(format string) string {
+	var verbs []byte
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Skip flags, width, precision and argument indexes
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0 {
+			i++
+		}
+		if i < len(format) && format[i] != '%' {
+			verbs = append(verbs, format[i])
+		}
+	}
+	sort.Slice(verbs, func(i, j int) bool { return verbs[i] < verbs[j] })
+
+	return string(verbs)
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Register adds entries to the default catalog.
+func Register(entries ...CatalogEntry) {
+	DefaultCatalog.Add(entries...)
+}
+
+// ValidateCatalog validates the default catalog.
+func ValidateCatalog() error {
+	return DefaultCatalog.Validate()
+}
+
+// MustValidateCatalog panics if the default catalog is invalid.
+// Call it from an init function or main, so that a misconfigured catalog
+// fails fast rather than at render time.
+func MustValidateCatalog() {
+	if err := ValidateCatalog(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,247 @@
+// Package catalog exports an error catalog as artifacts for the clients of
+// the API, so that they use the same codes, statuses and messages as the
+// application: a JSON document, and TypeScript declarations for frontends.
+//
+// As catalogs are built by the application at init, the export is meant to
+// be run by a small program importing the packages that fill the catalog,
+// e.g. from a go:generate directive:
+//
+//	//go:generate go run ./internal/gen-errors
+//
+// with the program writing the artifacts of weberr.DefaultCatalog:
+//
+//	catalog.WriteJSON(jsonFile, weberr.DefaultCatalog)
+//	catalog.WriteOpenAPI(openAPIFile, weberr.DefaultCatalog)
+//	catalog.WriteTypeScript(tsFile, weberr.DefaultCatalog)
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/zgalor/weberr"
+)
+
+// Document is the exported form of a catalog.
+type Document struct {
+	DefaultLocale string   `json:"defaultLocale"`
+	Locales       []string `json:"locales"`
+	Errors        []Error  `json:"errors"`
+}
+
+// Error is the exported form of a catalog entry.
+type Error struct {
+	Code         weberr.Code       `json:"code"`
+	Status       int               `json:"status"`
+	Message      string            `json:"message,omitempty"`
+	Translations map[string]string `json:"translations,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	// Fields maps the fields of the map details of the error to their JSON type
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// NewDocument returns the exported form of c, with its entries in the order
+// they were added.
+func NewDocument(c *weberr.Catalog) *Document {
+	doc := &Document{
+		DefaultLocale: c.DefaultLocale(),
+		Locales:       c.Locales(),
+		Errors:        []Error{},
+	}
+
+	for _, entry := range c.Entries() {
+		doc.Errors = append(doc.Errors, Error{
+			Code:         entry.Code,
+			Status:       entry.Type.HTTPStatus(),
+			Message:      entry.Message,
+			Translations: entry.Translations,
+			Description:  entry.Description,
+			Fields:       entry.Fields,
+		})
+	}
+
+	return doc
+}
+
+// WriteJSON writes c as an indented JSON Document.
+func WriteJSON(w io.Writer, c *weberr.Catalog) error {
+	data, err := json.MarshalIndent(NewDocument(c), "", "  ")
+	if err != nil {
+		return weberr.Wrapf(err, "encoding catalog")
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteOpenAPI writes the codes of c as an OpenAPI 3 document with only
+// components: an Error schema for the body of error responses, an ErrorCode
+// schema enumerating the codes, and a response per code, to be referenced
+// by the operations of the API, e.g.
+//
+//	$ref: "errors.json#/components/responses/orders.ORDER_NOT_FOUND"
+func WriteOpenAPI(w io.Writer, c *weberr.Catalog) error {
+	doc := NewDocument(c)
+
+	codes := []weberr.Code{}
+	responses := map[string]interface{}{}
+	for _, e := range doc.Errors {
+		codes = append(codes, e.Code)
+
+		description := e.Description
+		if description == "" {
+			description = e.Message
+		}
+		if description == "" {
+			description = http.StatusText(e.Status)
+		}
+
+		example := map[string]interface{}{"status": e.Status, "code": e.Code, "message": e.Message}
+		if e.Message == "" {
+			example["message"] = http.StatusText(e.Status)
+		}
+
+		responses[string(e.Code)] = map[string]interface{}{
+			"description": description,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":  map[string]string{"$ref": "#/components/schemas/Error"},
+					"example": example,
+				},
+			},
+		}
+	}
+
+	str := map[string]string{"type": "string"}
+	openAPI := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": "Errors", "version": "1"},
+		"paths":   map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"ErrorCode": map[string]interface{}{"type": "string", "enum": codes},
+				"Error": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"status", "message"},
+					"properties": map[string]interface{}{
+						"status":  map[string]string{"type": "integer"},
+						"code":    map[string]string{"$ref": "#/components/schemas/ErrorCode"},
+						"message": str,
+						"details": map[string]interface{}{"type": "array", "items": map[string]interface{}{}},
+						"error":   str,
+						"stack":   str,
+						"id":      str,
+					},
+				},
+			},
+			"responses": responses,
+		},
+	}
+
+	data, err := json.MarshalIndent(openAPI, "", "  ")
+	if err != nil {
+		return weberr.Wrapf(err, "encoding OpenAPI document")
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+var typeScriptTemplate = template.Must(template.New("ts").Funcs(template.FuncMap{
+	"quote":   quote,
+	"comment": comment,
+	"tsType":  tsType,
+	"sorted":  sorted,
+}).Parse(`// Code generated by weberr/catalog. DO NOT EDIT.
+
+/** The error codes the API may return. */
+export type ErrorCode =
+{{- range .Errors}}
+  | {{quote .Code}}
+{{- else}} never
+{{- end}};
+
+/** The definition of every error code. */
+export interface ErrorDefinitions {
+{{- range .Errors}}
+  {{- if .Description}}
+  /** {{comment .Description}} */
+  {{- end}}
+  {{quote .Code}}: {
+    status: {{.Status}};
+    message: {{quote .Message}};
+    fields: { {{- $fields := .Fields}}{{range sorted $fields}} {{quote .}}: {{tsType (index $fields .)}};{{end}} };
+  };
+{{- end}}
+}
+
+/** The body of an error response. */
+export interface ErrorBody<C extends ErrorCode = ErrorCode> {
+  status: number;
+  code?: C;
+  message: string;
+  details?: unknown[];
+  error?: string;
+  stack?: string;
+  id?: string;
+}
+`))
+
+// WriteTypeScript writes TypeScript declarations (a .d.ts file) for the
+// codes of c, their statuses, default messages and detail fields.
+func WriteTypeScript(w io.Writer, c *weberr.Catalog) error {
+	var buf bytes.Buffer
+	if err := typeScriptTemplate.Execute(&buf, NewDocument(c)); err != nil {
+		return weberr.Wrapf(err, "generating TypeScript declarations")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// quote returns s as a string literal, valid in JSON and TypeScript
+func quote(s interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// comment returns s safe to put in a /** */ comment, on a single line
+func comment(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "*/", "*\\/")), " ")
+}
+
+// tsType returns the TypeScript type of a JSON type
+func tsType(jsonType string) string {
+	switch jsonType {
+	case "string", "number", "boolean", "null":
+		return jsonType
+	case "integer":
+		return "number"
+	case "object":
+		return "Record<string, unknown>"
+	case "array":
+		return "unknown[]"
+	}
+
+	return "unknown"
+}
+
+// sorted returns the keys of m, sorted
+func sorted(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// testCatalog returns a catalog with entries covering the exported attributes
+func testCatalog() *weberr.Catalog {
+	c := weberr.NewCatalog("en", "fr")
+	c.Add(weberr.CatalogEntry{Code: "orders.ORDER_NOT_FOUND", Type: weberr.NotFound, Message: `order "%s" not found`,
+		Translations: map[string]string{"fr": `commande "%s" introuvable`}},
+		weberr.CatalogEntry{Code: "orders.CreateOrder.name.required", Type: weberr.BadRequest, Message: "name is required",
+			Fields: map[string]string{"rule": "string", "field": "string", "limits": "array"}},
+		weberr.CatalogEntry{Code: "orders.ORDER_DUP", Type: weberr.Conflict,
+			Description: "The order was already created,\nwith the same idempotency key. See */orders."})
+
+	return c
+}
+
+// golden compares got with the content of a golden file
+func golden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		os.WriteFile(path, got, 0644)
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testCatalog()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "testdata/errors.json.golden", buf.Bytes())
+
+	var doc Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Errors) != 3 || doc.Errors[0].Status != 404 || doc.Errors[1].Fields["field"] != "string" {
+		t.Errorf("got: %+v", doc)
+	}
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTypeScript(&buf, testCatalog()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "testdata/errors.d.ts.golden", buf.Bytes())
+
+	buf.Reset()
+	if err := WriteTypeScript(&buf, weberr.NewCatalog("en")); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("export type ErrorCode = never;")) {
+		t.Errorf("got:\n%s", buf.Bytes())
+	}
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOpenAPI(&buf, testCatalog()); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "testdata/errors.openapi.json.golden", buf.Bytes())
+
+	var doc struct {
+		Components struct {
+			Responses map[string]struct {
+				Description string `json:"description"`
+			} `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	responses := doc.Components.Responses
+	if len(responses) != 3 || responses["orders.ORDER_NOT_FOUND"].Description != `order "%s" not found` ||
+		responses["orders.ORDER_DUP"].Description == "" {
+		t.Errorf("got: %+v", responses)
+	}
+}
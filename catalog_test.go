@@ -0,0 +1,79 @@
+package weberr
+
+import (
+	"testing"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", ""},
+		{"no placeholders", ""},
+		{"%s has %d items", "ds"},
+		{"%[2]d items in %[1]s", "ds"},
+		{"100%% sure about %-10.2f", "f"},
+		{"trailing %", ""},
+	}
+	for _, tt := range tests {
+		got := formatVerbs(tt.format)
+		if got != tt.expected {
+			t.Errorf("%q: got: %q, want %q", tt.format, got, tt.expected)
+		}
+	}
+}
+
+func TestCatalogValidate(t *testing.T) {
+	valid := CatalogEntry{
+		Code:    "orders.ORDER_DUP",
+		Type:    Conflict,
+		Message: "order %s already exists",
+		Translations: map[string]string{
+			"fr": "la commande %s existe déjà",
+		},
+	}
+
+	tests := []struct {
+		entries  []CatalogEntry
+		problems int
+	}{
+		{nil, 0},
+		{[]CatalogEntry{valid}, 0},
+		{[]CatalogEntry{valid, valid}, 1},
+		{[]CatalogEntry{{Message: "no code", Type: BadRequest}}, 1},
+		{[]CatalogEntry{{Code: "A", Type: ErrorType(200)}}, 1},
+		{[]CatalogEntry{{Code: "A", Type: ErrorType(499)}}, 1},
+		{[]CatalogEntry{{Code: "A", Type: BadRequest, Message: "%s", Translations: map[string]string{"fr": "%d"}}}, 1},
+		{[]CatalogEntry{{Code: "A", Type: BadRequest, Translations: map[string]string{"de": ""}}}, 1},
+		{[]CatalogEntry{{Code: "A", Type: BadRequest, Translations: map[string]string{"FR": "", "EN": ""}}}, 0},
+	}
+	for i, tt := range tests {
+		c := NewCatalog("en", "fr")
+		c.Add(tt.entries...)
+
+		err := c.Validate()
+		got := 0
+		if err != nil {
+			got = len(err.(*CatalogError).Problems)
+		}
+		if got != tt.problems {
+			t.Errorf("case %d: got: %d problems (%v), want %d", i, got, err, tt.problems)
+		}
+	}
+}
+
+func TestMustValidateCatalog(t *testing.T) {
+	defer func(c *Catalog) { DefaultCatalog = c }(DefaultCatalog)
+
+	DefaultCatalog = NewCatalog("en")
+	Register(CatalogEntry{Code: "A", Type: NotFound})
+	if panics(MustValidateCatalog) {
+		t.Errorf("expected no panic")
+	}
+
+	Register(CatalogEntry{Code: "A", Type: NotFound})
+	if !panics(MustValidateCatalog) {
+		t.Errorf("expected a panic")
+	}
+}
@@ -0,0 +1,76 @@
+package weberr
+
+import "strings"
+
+// Layer is a level of an error chain.
+type Layer struct {
+	// Err is the error of the level
+	Err error
+	// Message is the message the level adds to the error it wraps. It is
+	// empty for the levels that only add attributes, such as a type or a
+	// stack trace.
+	Message string
+}
+
+// next returns the error wrapped by err, reached with Cause or Unwrap, or nil
+func next(err error) error {
+	switch e := err.(type) {
+	case causer:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+
+	return nil
+}
+
+// Chain returns err and the errors it wraps, outermost first.
+// Errors are unwrapped with Cause as in github.com/pkg/errors, or with
+// Unwrap as in the standard library.
+func Chain(err error) []error {
+	var chain []error
+	for ; err != nil; err = next(err) {
+		chain = append(chain, err)
+	}
+
+	return chain
+}
+
+// Root returns the innermost error wrapped by err, or err itself if it
+// doesn't wrap any.
+func Root(err error) error {
+	chain := Chain(err)
+	if len(chain) == 0 {
+		return nil
+	}
+
+	return chain[len(chain)-1]
+}
+
+// Layers returns the levels of the chain of err, outermost first, with the
+// message added at each level:
+//
+//	err := weberr.Wrapf(weberr.Wrapf(io.EOF, "reading order"), "loading cart")
+//
+// has the messages "loading cart", "reading order" and "EOF" at the levels
+// that add them.
+func Layers(err error) []Layer {
+	chain := Chain(err)
+	layers := make([]Layer, len(chain))
+
+	for i, err := range chain {
+		msg := err.Error()
+		if i+1 < len(chain) {
+			inner := chain[i+1].Error()
+			switch {
+			case msg == inner:
+				msg = ""
+			case strings.HasSuffix(msg, ": "+inner):
+				msg = strings.TrimSuffix(msg, ": "+inner)
+			}
+		}
+		layers[i] = Layer{Err: err, Message: msg}
+	}
+
+	return layers
+}
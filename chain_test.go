@@ -0,0 +1,59 @@
+package weberr
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	stdErr := fmt.Errorf("decoding: %w", io.EOF)
+	err := NotFound.Wrapf(Wrapf(stdErr, "reading order"), "loading cart")
+
+	tests := []struct {
+		err      error
+		length   int
+		expected error
+	}{
+		{nil, 0, nil},
+		{io.EOF, 1, io.EOF},
+		{stdErr, 2, io.EOF},
+		{Errorf("msg"), 3, nil},
+		{err, 7, io.EOF},
+	}
+	for _, tt := range tests {
+		chain := Chain(tt.err)
+		if len(chain) != tt.length {
+			t.Errorf("%v: got: %d errors %v, want %d", tt.err, len(chain), chain, tt.length)
+		}
+		if len(chain) > 0 && chain[0] != tt.err {
+			t.Errorf("got: %v, want %v first", chain[0], tt.err)
+		}
+		if tt.expected != nil && Root(tt.err) != tt.expected {
+			t.Errorf("got: %v, want %v", Root(tt.err), tt.expected)
+		}
+	}
+
+	if got := Root(Errorf("msg")).Error(); got != "msg" {
+		t.Errorf("got: %q, want %q", got, "msg")
+	}
+}
+
+func TestLayers(t *testing.T) {
+	err := SetUserMessage(Wrapf(Wrapf(fmt.Errorf("decoding: %w", io.EOF), "reading order"), "loading cart"), "msg")
+
+	var got []string
+	for _, layer := range Layers(err) {
+		if layer.Message != "" {
+			got = append(got, layer.Message)
+		}
+	}
+
+	expected := []string{"loading cart", "reading order", "decoding", "EOF"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("got: %q, want %q", got, expected)
+	}
+	if layers := Layers(err); layers[0].Message != "" || layers[0].Err != err {
+		t.Errorf("got: %+v, want the user message layer without message", layers[0])
+	}
+}
@@ -0,0 +1,50 @@
+// Package chiadapter translates weberr errors into chi responses.
+package chiadapter
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zgalor/weberr"
+)
+
+// HandlerFunc is an http.HandlerFunc returning an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler adapts a handler returning an error into an http.HandlerFunc.
+// If wr is nil, errors are written with weberr.WriteError.
+func Handler(wr *weberr.Writer, h HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			writeError(wr, w, r, err)
+		}
+	}
+}
+
+// Install makes the router render panics, unknown routes and unsupported
+// methods as weberr errors.
+// If wr is nil, errors are written with weberr.WriteError.
+func Install(r chi.Router, wr *weberr.Writer) {
+	if wr != nil {
+		r.Use(wr.Recover)
+	} else {
+		r.Use(weberr.Recover)
+	}
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(wr, w, r, weberr.NotFound.UserErrorf("route %s not found", r.URL.Path))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(wr, w, r, weberr.MethodNotAllowed.UserErrorf("method %s not allowed", r.Method))
+	})
+}
+
+// writeError writes err with wr, or with weberr.WriteError if wr is nil
+func writeError(wr *weberr.Writer, w http.ResponseWriter, r *http.Request, err error) {
+	if wr != nil {
+		wr.WriteError(w, r, err)
+		return
+	}
+
+	weberr.WriteError(w, r, err)
+}
@@ -0,0 +1,47 @@
+package chiadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/zgalor/weberr"
+)
+
+func TestChiAdapter(t *testing.T) {
+	r := chi.NewRouter()
+	Install(r, nil)
+	r.Get("/cluster", Handler(nil, func(w http.ResponseWriter, r *http.Request) error {
+		return weberr.NotFound.UserErrorf("cluster not found")
+	}))
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	r.Get("/ok", Handler(&weberr.Writer{}, func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	}))
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"GET", "/cluster", 404, "cluster not found"},
+		{"GET", "/panic", 500, "Internal Server Error"},
+		{"GET", "/ok", 200, "ok"},
+		{"GET", "/unknown", 404, "route /unknown not found"},
+		{"POST", "/cluster", 405, "method POST not allowed"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%s %s: got: %d %q, want %d %q", tt.method, tt.path, rec.Code, rec.Body, tt.status, tt.body)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package clientgen generates a Go client package from an error catalog,
+// with a sentinel error for every catalog entry.
+// Errors reconstructed by weberr.FromResponse match the sentinels of their
+// code with errors.Is:
+//
+//	if errors.Is(err, ordersapi.ErrOrderNotFound) {
+//
+// As catalogs are built by the application at init, the generator is meant
+// to be run by a small program importing the packages that fill the catalog,
+// e.g. from a go:generate directive:
+//
+//	//go:generate go run ./internal/gen-client
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/zgalor/weberr"
+)
+
+// Config configures the generated package.
+type Config struct {
+	// Package is the name of the generated package
+	Package string
+	// Namespace restricts the generated sentinels to the codes of a
+	// namespace, which is then omitted from their names.
+	Namespace string
+}
+
+// sentinel is a generated sentinel error
+type sentinel struct {
+	Name    string
+	Code    weberr.Code
+	Type    string
+	Message string
+}
+
+// typeNames are the names of the error types declared by weberr
+var typeNames = map[weberr.ErrorType]string{
+	weberr.NoType:                        "NoType",
+	weberr.BadRequest:                    "BadRequest",
+	weberr.Unauthorized:                  "Unauthorized",
+	weberr.PaymentRequired:               "PaymentRequired",
+	weberr.Forbidden:                     "Forbidden",
+	weberr.NotFound:                      "NotFound",
+	weberr.MethodNotAllowed:              "MethodNotAllowed",
+	weberr.NotAcceptable:                 "NotAcceptable",
+	weberr.ProxyAuthRequired:             "ProxyAuthRequired",
+	weberr.RequestTimeout:                "RequestTimeout",
+	weberr.Conflict:                      "Conflict",
+	weberr.Gone:                          "Gone",
+	weberr.LengthRequired:                "LengthRequired",
+	weberr.PreconditionFailed:            "PreconditionFailed",
+	weberr.RequestEntityTooLarge:         "RequestEntityTooLarge",
+	weberr.RequestURITooLong:             "RequestURITooLong",
+	weberr.UnsupportedMediaType:          "UnsupportedMediaType",
+	weberr.RequestedRangeNotSatisfiable:  "RequestedRangeNotSatisfiable",
+	weberr.ExpectationFailed:             "ExpectationFailed",
+	weberr.Teapot:                        "Teapot",
+	weberr.UnprocessableEntity:           "UnprocessableEntity",
+	weberr.Locked:                        "Locked",
+	weberr.FailedDependency:              "FailedDependency",
+	weberr.UpgradeRequired:               "UpgradeRequired",
+	weberr.PreconditionRequired:          "PreconditionRequired",
+	weberr.TooManyRequests:               "TooManyRequests",
+	weberr.RequestHeaderFieldsTooLarge:   "RequestHeaderFieldsTooLarge",
+	weberr.UnavailableForLegalReasons:    "UnavailableForLegalReasons",
+	weberr.InternalServerError:           "InternalServerError",
+	weberr.NotImplemented:                "NotImplemented",
+	weberr.BadGateway:                    "BadGateway",
+	weberr.ServiceUnavailable:            "ServiceUnavailable",
+	weberr.GatewayTimeout:                "GatewayTimeout",
+	weberr.HTTPVersionNotSupported:       "HTTPVersionNotSupported",
+	weberr.VariantAlsoNegotiates:         "VariantAlsoNegotiates",
+	weberr.InsufficientStorage:           "InsufficientStorage",
+	weberr.LoopDetected:                  "LoopDetected",
+	weberr.NotExtended:                   "NotExtended",
+	weberr.NetworkAuthenticationRequired: "NetworkAuthenticationRequired",
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Code generated by weberr/clientgen. DO NOT EDIT.
+
+// Package {{.Package}} declares the errors the API may return.
+package {{.Package}}
+
+import "github.com/zgalor/weberr"
+
+// The errors the API may return.
+// Errors with the same code match them with errors.Is.
+var (
+{{- range .Sentinels}}
+	// {{.Name}} is the {{.Code}} error{{if .Message}}: {{printf "%q" .Message}}{{end}}
+	{{.Name}} = {{.Type}}.Sentinel({{printf "%q" .Code}})
+{{- end}}
+)
+`))
+
+// Generate writes the source of the client package for the entries of c.
+func Generate(w io.Writer, cfg Config, c *weberr.Catalog) error {
+	prefix := ""
+	if cfg.Namespace != "" {
+		prefix = cfg.Namespace + "."
+	}
+
+	var sentinels []sentinel
+	names := map[string]weberr.Code{}
+	for _, entry := range c.Entries() {
+		if !strings.HasPrefix(string(entry.Code), prefix) {
+			continue
+		}
+
+		name := "Err" + identifier(strings.TrimPrefix(string(entry.Code), prefix))
+		if code, ok := names[name]; ok {
+			return weberr.Errorf("codes %q and %q both generate %s", code, entry.Code, name)
+		}
+		names[name] = entry.Code
+
+		sentinels = append(sentinels, sentinel{
+			Name:    name,
+			Code:    entry.Code,
+			Type:    typeExpr(entry.Type),
+			Message: entry.Message,
+		})
+	}
+
+	var buf bytes.Buffer
+	err := clientTemplate.Execute(&buf, struct {
+		Package   string
+		Sentinels []sentinel
+	}{cfg.Package, sentinels})
+	if err != nil {
+		return weberr.Wrapf(err, "generating client package")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return weberr.Wrapf(err, "formatting client package")
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// typeExpr returns the expression of an error type in the generated source:
+// its constant, e.g. weberr.NotFound, or a conversion for the other statuses
+func typeExpr(errorType weberr.ErrorType) string {
+	if name, ok := typeNames[errorType]; ok {
+		return "weberr." + name
+	}
+
+	return fmt.Sprintf("weberr.ErrorType(%d)", int(errorType))
+}
+
+// identifier converts a code such as "ORDER_NOT_FOUND" or
+// "createOrder.name.required" into a Go identifier in camel case.
+// The items of a field, e.g. "tags[]", are named "TagsItems".
+func identifier(code string) string {
+	code = strings.ReplaceAll(code, "[]", ".items")
+
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(code, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.ToUpper(word) == word {
+			word = strings.ToLower(word)
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return fmt.Sprintf("Code%s", b.String())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package clientgen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"ORDER_NOT_FOUND", "OrderNotFound"},
+		{"createOrder.name.required", "CreateOrderNameRequired"},
+		{"orders.ORDER-DUP", "OrdersOrderDup"},
+		{"createOrder.tags[].required", "CreateOrderTagsItemsRequired"},
+		{"404", "Code404"},
+	}
+	for _, tt := range tests {
+		if got := identifier(tt.code); got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestTypeExpr(t *testing.T) {
+	tests := []struct {
+		errorType weberr.ErrorType
+		expected  string
+	}{
+		{weberr.NotFound, "weberr.NotFound"},
+		{weberr.NoType, "weberr.NoType"},
+		{weberr.NetworkAuthenticationRequired, "weberr.NetworkAuthenticationRequired"},
+		{weberr.ErrorType(499), "weberr.ErrorType(499)"},
+	}
+	for _, tt := range tests {
+		if got := typeExpr(tt.errorType); got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	c := weberr.NewCatalog("en")
+	c.Add(weberr.CatalogEntry{Code: "orders.ORDER_NOT_FOUND", Type: weberr.NotFound, Message: "order %s not found"},
+		weberr.CatalogEntry{Code: "orders.ORDER_DUP", Type: weberr.Conflict},
+		weberr.CatalogEntry{Code: "payments.CARD_DECLINED", Type: weberr.PaymentRequired})
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, Config{Package: "ordersapi", Namespace: "orders"}, c); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/ordersapi.go.golden"
+	if *update {
+		os.WriteFile(golden, buf.Bytes(), 0644)
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.Bytes(), expected)
+	}
+
+	c.Add(weberr.CatalogEntry{Code: "orders.order-dup", Type: weberr.Conflict})
+	if err := Generate(&buf, Config{Package: "ordersapi", Namespace: "orders"}, c); err == nil {
+		t.Errorf("expected a name collision error")
+	}
+}
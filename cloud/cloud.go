@@ -0,0 +1,135 @@
+// Package cloud translates the errors of cloud provider SDKs into weberr
+// errors: the API errors of the AWS SDK for Go v2, and of the other SDKs
+// generated by smithy.
+package cloud
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/zgalor/weberr"
+)
+
+// code is the weberr type and user message of a provider error code
+type code struct {
+	errorType   weberr.ErrorType
+	userMessage string
+}
+
+var (
+	notFound    = code{weberr.NotFound, "The resource was not found"}
+	denied      = code{weberr.Forbidden, "You are not allowed to perform this operation"}
+	throttled   = code{weberr.TooManyRequests, "Too many requests, please retry later"}
+	timedOut    = code{weberr.GatewayTimeout, "The operation timed out"}
+	invalid     = code{weberr.BadRequest, "The request is invalid"}
+	unavailable = code{weberr.ServiceUnavailable, "The service is unavailable, please retry later"}
+	// credentials are rejected credentials of the service, not of its client
+	credentials = code{weberr.InternalServerError, ""}
+)
+
+// codes maps the error codes of AWS services to weberr types
+var codes = map[string]code{
+	"NoSuchKey":                 notFound,
+	"NoSuchBucket":              notFound,
+	"NoSuchEntity":              notFound,
+	"NotFound":                  notFound,
+	"ResourceNotFoundException": notFound,
+
+	"AccessDenied":          denied,
+	"AccessDeniedException": denied,
+	"UnauthorizedOperation": denied,
+	"Forbidden":             denied,
+
+	"Throttling":                             throttled,
+	"ThrottlingException":                    throttled,
+	"ThrottledException":                     throttled,
+	"TooManyRequestsException":               throttled,
+	"RequestLimitExceeded":                   throttled,
+	"SlowDown":                               throttled,
+	"ProvisionedThroughputExceededException": throttled,
+
+	"RequestTimeout":          timedOut,
+	"RequestTimeoutException": timedOut,
+
+	"ValidationException":       invalid,
+	"ValidationError":           invalid,
+	"InvalidParameterValue":     invalid,
+	"InvalidParameterException": invalid,
+	"MalformedPolicyDocument":   invalid,
+
+	"ConditionalCheckFailedException": {weberr.PreconditionFailed, "The resource was modified concurrently, please retry"},
+	"PreconditionFailed":              {weberr.PreconditionFailed, "The resource was modified concurrently, please retry"},
+	"BucketAlreadyExists":             {weberr.Conflict, "The resource already exists"},
+	"ResourceInUseException":          {weberr.Conflict, "The resource is in use"},
+
+	"ServiceUnavailable":          unavailable,
+	"ServiceUnavailableException": unavailable,
+	"InternalError":               unavailable,
+	"InternalFailure":             unavailable,
+
+	"ExpiredToken":                credentials,
+	"ExpiredTokenException":       credentials,
+	"InvalidClientTokenId":        credentials,
+	"UnrecognizedClientException": credentials,
+	"InvalidAccessKeyId":          credentials,
+	"SignatureDoesNotMatch":       credentials,
+	"IncompleteSignature":         credentials,
+	"MissingAuthenticationToken":  credentials,
+}
+
+// FromAWSError converts an API error of the AWS SDK for Go v2, or of another
+// SDK generated by smithy, into a weberr error wrapping it, with the type and
+// a user message matching its error code. Errors with an unknown code are
+// BadGateway errors for server faults and 5xx statuses, InternalServerError
+// errors for a 401 status, as the credentials of the service were rejected,
+// and get the type of other 4xx statuses, or BadRequest for client faults.
+// The provider error code is added as a detail, with the service and
+// operation that failed, and the request ID if any.
+// Other errors are returned unchanged.
+func FromAWSError(err error) error {
+	var apiErr smithy.APIError
+	if !stderrors.As(err, &apiErr) {
+		return err
+	}
+
+	c, ok := codes[apiErr.ErrorCode()]
+	if !ok {
+		c.errorType = weberr.BadGateway
+		status := 0
+		var statusErr interface{ HTTPStatusCode() int }
+		if stderrors.As(err, &statusErr) {
+			status = statusErr.HTTPStatusCode()
+		}
+		switch {
+		case apiErr.ErrorFault() == smithy.FaultServer || status >= 500:
+			// The provider failed, not the request of the client
+		case status == http.StatusUnauthorized:
+			c.errorType = weberr.InternalServerError
+		case status >= 400:
+			c.errorType = weberr.ErrorType(status)
+		case apiErr.ErrorFault() == smithy.FaultClient:
+			c.errorType = weberr.BadRequest
+		}
+	}
+
+	if c.userMessage != "" {
+		err = c.errorType.UserWrapf(err, "%s", c.userMessage)
+	} else {
+		err = c.errorType.Set(err)
+	}
+
+	detail := map[string]string{"provider": "aws", "code": apiErr.ErrorCode()}
+	var opErr *smithy.OperationError
+	if stderrors.As(err, &opErr) {
+		detail["service"] = opErr.Service()
+		detail["operation"] = opErr.Operation()
+	}
+	var requestID interface{ ServiceRequestID() string }
+	if stderrors.As(err, &requestID) && requestID.ServiceRequestID() != "" {
+		detail["requestId"] = requestID.ServiceRequestID()
+	}
+
+	return weberr.AddDetails(err, detail)
+}
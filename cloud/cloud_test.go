@@ -0,0 +1,83 @@
+package cloud
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/zgalor/weberr"
+)
+
+// requestIDError is an AWS response error with a request ID
+type requestIDError struct {
+	*smithyhttp.ResponseError
+}
+
+func (e requestIDError) ServiceRequestID() string { return "req-1" }
+
+// operationError returns a failure of an S3 operation, as returned by the SDK
+func operationError(status int, apiErr error) error {
+	return &smithy.OperationError{
+		ServiceID:     "S3",
+		OperationName: "GetObject",
+		Err: requestIDError{&smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      apiErr,
+		}},
+	}
+}
+
+func TestFromAWSError(t *testing.T) {
+	tests := []struct {
+		err       error
+		errorType weberr.ErrorType
+		code      string
+	}{
+		{operationError(404, &smithy.GenericAPIError{Code: "NoSuchKey", Message: "The specified key does not exist."}), weberr.NotFound, "NoSuchKey"},
+		{operationError(403, &smithy.GenericAPIError{Code: "AccessDenied"}), weberr.Forbidden, "AccessDenied"},
+		{operationError(400, &smithy.GenericAPIError{Code: "ThrottlingException"}), weberr.TooManyRequests, "ThrottlingException"},
+		{operationError(400, &smithy.GenericAPIError{Code: "RequestTimeout"}), weberr.GatewayTimeout, "RequestTimeout"},
+		{operationError(409, &smithy.GenericAPIError{Code: "SomethingNew"}), weberr.Conflict, "SomethingNew"},
+		{&smithy.GenericAPIError{Code: "SomethingNew", Fault: smithy.FaultClient}, weberr.BadRequest, "SomethingNew"},
+		{&smithy.GenericAPIError{Code: "SomethingNew", Fault: smithy.FaultServer}, weberr.BadGateway, "SomethingNew"},
+		{fmt.Errorf("provisioning: %w", &smithy.GenericAPIError{Code: "SlowDown"}), weberr.TooManyRequests, "SlowDown"},
+		{operationError(500, &smithy.GenericAPIError{Code: "SomethingNew", Fault: smithy.FaultServer}), weberr.BadGateway, "SomethingNew"},
+		{operationError(503, &smithy.GenericAPIError{Code: "SomethingNew"}), weberr.BadGateway, "SomethingNew"},
+		{operationError(400, &smithy.GenericAPIError{Code: "SomethingNew", Fault: smithy.FaultServer}), weberr.BadGateway, "SomethingNew"},
+		{operationError(401, &smithy.GenericAPIError{Code: "SomethingNew", Fault: smithy.FaultClient}), weberr.InternalServerError, "SomethingNew"},
+		{operationError(403, &smithy.GenericAPIError{Code: "ExpiredToken", Fault: smithy.FaultClient}), weberr.InternalServerError, "ExpiredToken"},
+	}
+	for _, tt := range tests {
+		err := FromAWSError(tt.err)
+		if got := weberr.GetType(err); got != tt.errorType {
+			t.Errorf("%v: got: %v, want %v", tt.err, got, tt.errorType)
+		}
+
+		details := weberr.GetDetails(err)
+		detail, ok := details[len(details)-1].(map[string]string)
+		if !ok || detail["provider"] != "aws" || detail["code"] != tt.code {
+			t.Errorf("%v: got: details %v", tt.err, details)
+		}
+		if err.Error() != tt.err.Error() {
+			t.Errorf("got: %q, want the message %q", err, tt.err)
+		}
+	}
+
+	err := FromAWSError(tests[0].err)
+	details := weberr.GetDetails(err)
+	expected := map[string]string{"provider": "aws", "code": "NoSuchKey", "service": "S3", "operation": "GetObject", "requestId": "req-1"}
+	if fmt.Sprint(details[0]) != fmt.Sprint(expected) {
+		t.Errorf("got: %v, want %v", details[0], expected)
+	}
+	if weberr.GetUserMessage(err) != "The resource was not found" {
+		t.Errorf("got: %q", weberr.GetUserMessage(err))
+	}
+
+	if FromAWSError(io.EOF) != io.EOF || FromAWSError(nil) != nil {
+		t.Errorf("expected other errors unchanged")
+	}
+}
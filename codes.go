@@ -0,0 +1,157 @@
+package weberr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Code is a machine readable error code, optionally prefixed with a namespace,
+// e.g. "payments.ORDER_DUP".
+type Code string
+
+// Namespace returns the namespace of the code, or "" if it has none.
+func (c Code) Namespace() string {
+	if i := strings.LastIndex(string(c), "."); i >= 0 {
+		return string(c[:i])
+	}
+
+	return ""
+}
+
+// Name returns the code without its namespace.
+func (c Code) Name() string {
+	if i := strings.LastIndex(string(c), "."); i >= 0 {
+		return string(c[i+1:])
+	}
+
+	return string(c)
+}
+
+// Namespace owns a set of error codes, usually one per package or service.
+// Namespaces and codes are meant to be registered in package-level variables,
+// so that collisions panic at init rather than silently reusing a code:
+//
+//	var payments = weberr.NewNamespace("payments")
+//	var OrderDup = payments.Code("ORDER_DUP")
+type Namespace struct {
+	name string
+}
+
+var (
+	registryMu sync.Mutex
+	namespaces = map[string]bool{}
+	codes      = map[Code]bool{}
+)
+
+// NewNamespace registers a new namespace.
+// It panics if the name is empty, malformed or already registered.
+func NewNamespace(name string) Namespace {
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		panic(fmt.Sprintf("weberr: invalid namespace %q", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if namespaces[name] {
+		panic(fmt.Sprintf("weberr: namespace %q registered twice", name))
+	}
+	namespaces[name] = true
+
+	return Namespace{name: name}
+}
+
+// Name returns the name of the namespace.
+func (ns Namespace) Name() string { return ns.name }
+
+// Code registers a new code in the namespace.
+// It panics if the name is empty, contains a dot or is already registered.
+func (ns Namespace) Code(name string) Code {
+	if name == "" || strings.Contains(name, ".") {
+		panic(fmt.Sprintf("weberr: invalid code %q in namespace %q", name, ns.name))
+	}
+
+	code := Code(ns.name + "." + name)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if codes[code] {
+		panic(fmt.Sprintf("weberr: code %q registered twice", code))
+	}
+	codes[code] = true
+
+	return code
+}
+
+// RegisteredCodes returns all codes registered through namespaces, sorted.
+func RegisteredCodes() []Code {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	result := make([]Code, 0, len(codes))
+	for code := range codes {
+		result = append(result, code)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
+
+// coder identifies an error with a code
+type coder interface {
+	Code() Code
+}
+
+// Code returns the error code
+func (c *Error) Code() Code { return c.code }
+
+// GetCode returns the error code for all errors.
+// If error is not `coder` returns empty code.
+func GetCode(err error) Code {
+	if codeErr, ok := err.(coder); ok {
+		return codeErr.Code()
+	}
+
+	return ""
+}
+
+// Is reports whether target has the same code as the error, so that
+// errors.Is(err, target) matches errors by code, e.g. against sentinel errors
+// of a generated client package.
+func (c *Error) Is(target error) bool {
+	if c.code == "" {
+		return false
+	}
+
+	return GetCode(target) == c.code
+}
+
+// SetCode sets the code of an error.
+// Also sets error type (or preserves existing type if called on NoType).
+func (errorType ErrorType) SetCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+
+	c := inherit(err)
+	c.code = code
+	if errorType != NoType {
+		c.errorType = errorType
+	}
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
+
+// SetCode sets the code of an error
+func SetCode(err error, code Code) error {
+	return NoType.SetCode(err, code)
+}
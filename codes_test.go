@@ -0,0 +1,114 @@
+package weberr
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// Helper function to check that f panics
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+
+	return false
+}
+
+func TestNamespace(t *testing.T) {
+	ns := NewNamespace("test.orders")
+	dup := ns.Code("ORDER_DUP")
+
+	if dup != "test.orders.ORDER_DUP" {
+		t.Errorf("got: %q, want %q", dup, "test.orders.ORDER_DUP")
+	}
+	if dup.Namespace() != "test.orders" || dup.Name() != "ORDER_DUP" {
+		t.Errorf("got: %q %q, want %q %q", dup.Namespace(), dup.Name(), "test.orders", "ORDER_DUP")
+	}
+
+	found := false
+	for _, code := range RegisteredCodes() {
+		found = found || code == dup
+	}
+	if !found {
+		t.Errorf("expected %q in registered codes", dup)
+	}
+
+	// Collisions and malformed names
+	tests := []func(){
+		func() { NewNamespace("test.orders") },
+		func() { NewNamespace("") },
+		func() { NewNamespace("test.") },
+		func() { ns.Code("ORDER_DUP") },
+		func() { ns.Code("") },
+		func() { ns.Code("A.B") },
+	}
+	for i, f := range tests {
+		if !panics(f) {
+			t.Errorf("case %d: expected a panic", i)
+		}
+	}
+}
+
+// Code logic tested:
+// Default: empty code
+// Code is preserved by wrapping
+// Code is overridden by SetCode
+func TestGetCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected Code
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{SetCode(nil, "A"), ""},
+		{SetCode(io.EOF, "A"), "A"},
+		{Wrapf(SetCode(io.EOF, "A"), "msg"), "A"},
+		{UserWrapf(SetCode(io.EOF, "A"), "msg"), "A"},
+		{AddDetails(SetCode(io.EOF, "A"), "foo"), "A"},
+		{NotFound.Set(SetCode(io.EOF, "A")), "A"},
+		{SetUserMessage(SetCode(io.EOF, "A"), "msg"), "A"},
+		{SetCode(SetCode(io.EOF, "A"), "B"), "B"},
+	}
+	for _, tt := range tests {
+		got := GetCode(tt.err)
+		if got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+
+	if got := GetType(NotFound.SetCode(BadRequest.Set(io.EOF), "A")); got != NotFound {
+		t.Errorf("got: %v, want %v", got, NotFound)
+	}
+	if got := GetType(SetCode(BadRequest.Set(io.EOF), "A")); got != BadRequest {
+		t.Errorf("got: %v, want %v", got, BadRequest)
+	}
+}
+
+// codeSentinel is a sentinel error with a code
+type codeSentinel Code
+
+func (s codeSentinel) Error() string { return string(s) }
+
+func (s codeSentinel) Code() Code { return Code(s) }
+
+func TestIsCode(t *testing.T) {
+	sentinel := codeSentinel("A")
+
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{io.EOF, false},
+		{Errorf("A"), false},
+		{SetCode(io.EOF, "A"), true},
+		{SetCode(io.EOF, "B"), false},
+		{Wrapf(UserWrapf(SetCode(io.EOF, "A"), "msg"), "msg"), true},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, sentinel); got != tt.expected {
+			t.Errorf("%v: got: %v, want %v", tt.err, got, tt.expected)
+		}
+	}
+}
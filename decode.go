@@ -0,0 +1,186 @@
+package weberr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxBodySize is the largest error body DecodeBody accepts.
+	MaxBodySize = 1 << 20
+	// MaxBodyDepth is the deepest nesting of JSON values DecodeBody accepts.
+	MaxBodyDepth = 32
+)
+
+// DecodeBody parses an error body produced by Writer or ToJSON, typically
+// received from another service. Unknown fields are ignored, and bodies
+// exceeding MaxBodySize or MaxBodyDepth are rejected before being decoded.
+func DecodeBody(data []byte) (*Body, error) {
+	if len(data) > MaxBodySize {
+		return nil, Errorf("error body exceeds %d bytes", MaxBodySize)
+	}
+
+	if err := checkDepth(data, MaxBodyDepth); err != nil {
+		return nil, err
+	}
+
+	body := new(Body)
+	if err := json.Unmarshal(data, body); err != nil {
+		return nil, Wrapf(err, "invalid error body")
+	}
+
+	return body, nil
+}
+
+// checkDepth checks data is valid JSON nested at most maxDepth deep
+func checkDepth(data []byte, maxDepth int) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return Wrapf(err, "invalid error body")
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				return Errorf("error body nested deeper than %d", maxDepth)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// Err reconstructs the error described by the body.
+// The type is derived from the status, and the internal message defaults
+// to the user message when the body doesn't expose it.
+func (b *Body) Err() error {
+	errorType := NoType
+	if b.Status >= 400 && b.Status <= 599 {
+		errorType = ErrorType(b.Status)
+	}
+
+	msg := b.Error
+	if msg == "" {
+		msg = b.Message
+	}
+
+	c := &Error{
+		error:       errors.New(msg),
+		errorType:   errorType,
+		userMessage: b.Message,
+		code:        b.Code,
+		details:     b.Details,
+	}
+	if b.ID != "" {
+		c.id.Store(&errorID{id: b.ID})
+	}
+
+	return c
+}
+
+// FromJSON reconstructs an error from its JSON representation.
+// If data can't be decoded, the returned error describes the decoding failure.
+func FromJSON(data []byte) error {
+	body, err := DecodeBody(data)
+	if err != nil {
+		return err
+	}
+
+	return body.Err()
+}
+
+// problem is an RFC 7807 application/problem+json body
+type problem struct {
+	Type    string        `json:"type"`
+	Title   string        `json:"title"`
+	Status  int           `json:"status"`
+	Detail  string        `json:"detail,omitempty"`
+	Code    Code          `json:"code,omitempty"`
+	Details []interface{} `json:"details,omitempty"`
+	ID      string        `json:"id,omitempty"`
+}
+
+// FromResponse reconstructs the error returned by another service from its
+// response, or returns nil if the status isn't an error status.
+// The type is derived from the status code, and the user message, code and
+// details are restored from an application/problem+json body or from the
+// package's JSON format. Other bodies only contribute the status.
+// The retry delay is restored from the Retry-After header.
+// The body is read, at most MaxBodySize bytes of it, but not closed.
+func FromResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	errorType := NoType
+	if resp.StatusCode <= 599 {
+		errorType = ErrorType(resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize+1))
+	if err != nil {
+		return errorType.Wrapf(err, "reading error response with status %d", resp.StatusCode)
+	}
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	body, err := decodeResponseBody(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return WithRetryAfter(errorType.Errorf("error response with status %d", resp.StatusCode), retryAfter)
+	}
+
+	// The status of the response prevails over the one of the body
+	body.Status = resp.StatusCode
+	if body.Error == "" && body.Message == "" {
+		body.Error = fmt.Sprintf("error response with status %d", resp.StatusCode)
+	}
+
+	c := body.Err().(*Error)
+	c.retryAfter = retryAfter
+
+	return c
+}
+
+// decodeResponseBody decodes a problem+json or JSON error body
+func decodeResponseBody(contentType string, data []byte) (*Body, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "application/problem+json":
+		if len(data) > MaxBodySize {
+			return nil, Errorf("error body exceeds %d bytes", MaxBodySize)
+		}
+		if err := checkDepth(data, MaxBodyDepth); err != nil {
+			return nil, err
+		}
+
+		p := new(problem)
+		if err := json.Unmarshal(data, p); err != nil {
+			return nil, Wrapf(err, "invalid problem body")
+		}
+
+		message := p.Detail
+		if message == "" {
+			message = p.Title
+		}
+
+		return &Body{Status: p.Status, Code: p.Code, Message: message, Details: p.Details, ID: p.ID}, nil
+	case "application/json":
+		return DecodeBody(data)
+	default:
+		return nil, Errorf("unsupported error body content type %q", contentType)
+	}
+}
@@ -0,0 +1,157 @@
+package weberr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Decoding logic tested:
+// Round trip through ToJSON preserves type, code, user message and details
+// Unknown fields are ignored
+// Oversized, too deep and malformed bodies are rejected
+func TestFromJSON(t *testing.T) {
+	defer SetExposure(GetExposure())
+	SetExposure(ExposeDetails)
+
+	orig := AddDetails(SetCode(NotFound.UserWrapf(io.EOF, "Not here"), "orders.NOT_FOUND"), "foo")
+	data, err := ToJSON(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromJSON(data)
+	if GetType(got) != NotFound || GetCode(got) != "orders.NOT_FOUND" || GetUserMessage(got) != "Not here" ||
+		!compare(GetDetails(got), []interface{}{"foo"}) || got.Error() != "Not here" {
+		t.Errorf("got: %v %q %q %v %q", GetType(got), GetCode(got), GetUserMessage(got), GetDetails(got), got)
+	}
+
+	got = FromJSON([]byte(`{"status":409,"message":"100% sure","error":"internal","extra":{"a":[1]}}`))
+	if GetType(got) != Conflict || GetUserMessage(got) != "100% sure" || got.Error() != "internal" {
+		t.Errorf("got: %v %q %q", GetType(got), GetUserMessage(got), got)
+	}
+
+	if got := GetType(FromJSON([]byte(`{"status":200}`))); got != NoType {
+		t.Errorf("got: %v, want %v", got, NoType)
+	}
+
+	tests := []string{
+		"",
+		"{",
+		`{"status":"404"}`,
+		`{"status":400}}`,
+		strings.Repeat("[", MaxBodyDepth+1) + strings.Repeat("]", MaxBodyDepth+1),
+		`{"message":"` + strings.Repeat("a", MaxBodySize) + `"}`,
+	}
+	for _, tt := range tests {
+		if _, err := DecodeBody([]byte(tt)); err == nil {
+			t.Errorf("%.20q: expected an error", tt)
+		}
+	}
+}
+
+func FuzzFromJSON(f *testing.F) {
+	f.Add([]byte(`{"status":404,"code":"orders.NOT_FOUND","message":"Not here","details":["foo",{"a":1}]}`))
+	f.Add([]byte(`{"status":500,"error":"internal: EOF","stack":"trace"}`))
+	f.Add([]byte(`[[[[{}]]]]`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := FromJSON(data)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		_ = err.Error()
+		_ = GetStackTrace(err)
+	})
+}
+
+// response builds a response with the given status, content type and body
+func response(status int, contentType string, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", contentType)
+	rec.WriteHeader(status)
+	rec.WriteString(body)
+
+	return rec.Result()
+}
+
+// Response decoding logic tested:
+// Non-error statuses return nil
+// The type always comes from the status code
+// problem+json and the package's JSON restore user message, code and details
+// Other and malformed bodies only contribute the status
+func TestFromResponse(t *testing.T) {
+	if err := FromResponse(response(200, "application/json", `{"status":404}`)); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, nil, SetCode(NotFound.UserErrorf("Not here"), "orders.NOT_FOUND"))
+	written := rec.Result()
+
+	tests := []struct {
+		resp     *http.Response
+		errType  ErrorType
+		code     Code
+		userMsg  string
+		details  []interface{}
+		expected string
+	}{
+		{written, NotFound, "orders.NOT_FOUND", "Not here", nil, "Not here"},
+		{response(409, "application/problem+json", `{"type":"about:blank","title":"Conflict","status":409,"detail":"Already exists","code":"A","details":["foo"]}`),
+			Conflict, "A", "Already exists", []interface{}{"foo"}, "Already exists"},
+		{response(429, "application/problem+json; charset=utf-8", `{"title":"Slow down"}`),
+			TooManyRequests, "", "Slow down", nil, "Slow down"},
+		{response(502, "text/html", `<html>Bad Gateway</html>`), BadGateway, "", "", nil, "error response with status 502"},
+		{response(400, "application/json", `{"status":`), BadRequest, "", "", nil, "error response with status 400"},
+		{response(500, "application/json", `{"status":404,"message":"","error":"internal"}`), InternalServerError, "", "", nil, "internal"},
+		{response(600, "application/json", `{}`), NoType, "", "", nil, "error response with status 600"},
+	}
+	for _, tt := range tests {
+		got := FromResponse(tt.resp)
+		if GetType(got) != tt.errType || GetCode(got) != tt.code || GetUserMessage(got) != tt.userMsg ||
+			!compare(GetDetails(got), tt.details) || got.Error() != tt.expected {
+			t.Errorf("got: %v %q %q %v %q, want %v %q %q %v %q",
+				GetType(got), GetCode(got), GetUserMessage(got), GetDetails(got), got,
+				tt.errType, tt.code, tt.userMsg, tt.details, tt.expected)
+		}
+	}
+
+	// The retry delay written by WriteError is restored
+	rec = httptest.NewRecorder()
+	WriteError(rec, nil, WithRetryAfter(ServiceUnavailable.UserErrorf("Later"), 3*time.Second))
+	if got := GetRetryAfter(FromResponse(rec.Result())); got != 3*time.Second {
+		t.Errorf("got: %v, want 3s", got)
+	}
+	unreadable := response(503, "text/html", `<html>`)
+	unreadable.Header.Set("Retry-After", "5")
+	if got := GetRetryAfter(FromResponse(unreadable)); got != 5*time.Second {
+		t.Errorf("got: %v, want 5s", got)
+	}
+}
+
+func FuzzFromResponse(f *testing.F) {
+	f.Add(404, "application/json", []byte(`{"status":404,"message":"Not here"}`))
+	f.Add(409, "application/problem+json", []byte(`{"title":"Conflict","detail":"Already exists","details":[{}]}`))
+	f.Add(500, "text/plain", []byte("boom"))
+
+	f.Fuzz(func(t *testing.T, status int, contentType string, data []byte) {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       io.NopCloser(strings.NewReader(string(data))),
+		}
+
+		err := FromResponse(resp)
+		if (err != nil) != (status >= 400) {
+			t.Fatalf("status %d: got %v", status, err)
+		}
+		if err != nil {
+			_ = err.Error()
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Package echoadapter translates weberr errors into Echo responses.
+package echoadapter
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zgalor/weberr"
+)
+
+// HTTPErrorHandler returns an echo.HTTPErrorHandler writing errors returned
+// by handlers, to be set as e.HTTPErrorHandler.
+// Echo's own *echo.HTTPError (e.g. for unknown routes) is converted into the
+// weberr type matching its code.
+// If wr is nil, errors are written with weberr.WriteError.
+func HTTPErrorHandler(wr *weberr.Writer) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		err = FromHTTPError(err)
+		if wr != nil {
+			wr.WriteError(c.Response(), c.Request(), err)
+			return
+		}
+
+		weberr.WriteError(c.Response(), c.Request(), err)
+	}
+}
+
+// FromHTTPError converts an *echo.HTTPError into a weberr error of the
+// matching type, with its message as user message.
+// Other errors are returned unchanged.
+func FromHTTPError(err error) error {
+	he, ok := err.(*echo.HTTPError)
+	if !ok {
+		return err
+	}
+
+	errorType := weberr.NoType
+	if he.Code >= 400 && he.Code <= 599 {
+		errorType = weberr.ErrorType(he.Code)
+	}
+
+	msg := fmt.Sprint(he.Message)
+	if he.Internal != nil {
+		return errorType.UserWrapf(he.Internal, "%s", msg)
+	}
+
+	return errorType.UserErrorf("%s", msg)
+}
@@ -0,0 +1,57 @@
+package echoadapter
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zgalor/weberr"
+)
+
+func TestFromHTTPError(t *testing.T) {
+	tests := []struct {
+		err      error
+		errType  weberr.ErrorType
+		usrMsg   string
+		expected string
+	}{
+		{io.EOF, weberr.NoType, "", "EOF"},
+		{echo.ErrNotFound, weberr.NotFound, "Not Found", "Not Found"},
+		{echo.NewHTTPError(400, "bad %d").SetInternal(io.EOF), weberr.BadRequest, "bad %d", "EOF"},
+		{echo.NewHTTPError(200), weberr.NoType, "OK", "OK"},
+	}
+	for _, tt := range tests {
+		got := FromHTTPError(tt.err)
+		if weberr.GetType(got) != tt.errType || weberr.GetUserMessage(got) != tt.usrMsg || got.Error() != tt.expected {
+			t.Errorf("got: %v %q %q, want %v %q %q", weberr.GetType(got), weberr.GetUserMessage(got), got,
+				tt.errType, tt.usrMsg, tt.expected)
+		}
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(nil)
+	e.GET("/cluster", func(c echo.Context) error {
+		return weberr.NotFound.UserErrorf("cluster not found")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/cluster", 404, "cluster not found"},
+		{"/unknown", 404, "Not Found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%s: got: %d %q, want %d %q", tt.path, rec.Code, rec.Body, tt.status, tt.body)
+		}
+	}
+}
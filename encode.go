@@ -0,0 +1,151 @@
+package weberr
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Encoder renders the body of an error response in a media type.
+type Encoder func(body *Body) ([]byte, error)
+
+// encoding is a registered encoder and the media type it produces
+type encoding struct {
+	mediaType   string
+	contentType string
+	encode      Encoder
+}
+
+var (
+	// encodings holds the []encoding of registered encoders, in registration order
+	encodings atomic.Value
+	// encodingsMu serializes updates of encodings
+	encodingsMu sync.Mutex
+)
+
+func init() {
+	RegisterEncoder("application/json; charset=utf-8", encodeJSON)
+	RegisterEncoder("application/problem+json", encodeProblem)
+	RegisterEncoder("application/xml; charset=utf-8", encodeXML)
+	RegisterEncoder("text/plain; charset=utf-8", encodeText)
+}
+
+// RegisterEncoder registers the encoder of a content type, e.g.
+// "application/xml; charset=utf-8", replacing the one registered for its
+// media type if any. The Writer uses it for the requests whose Accept
+// header prefers its media type.
+// JSON is the default, and application/problem+json (RFC 7807), XML and
+// plain text are registered too.
+// It should be called during initialization, before errors are rendered.
+func RegisterEncoder(contentType string, enc Encoder) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		panic(fmt.Sprintf("weberr: invalid content type %q: %v", contentType, err))
+	}
+
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+
+	current, _ := encodings.Load().([]encoding)
+	updated := make([]encoding, 0, len(current)+1)
+	for _, e := range current {
+		if e.mediaType != mediaType {
+			updated = append(updated, e)
+		}
+	}
+	encodings.Store(append(updated, encoding{mediaType, contentType, enc}))
+}
+
+// negotiate returns the encoding preferred by a request, JSON by default
+func negotiate(r *http.Request) encoding {
+	registered, _ := encodings.Load().([]encoding)
+	if r != nil {
+		for _, accepted := range parseAccept(r.Header.Get("Accept")) {
+			accepted = strings.ToLower(accepted)
+			if accepted == "*/*" {
+				break
+			}
+
+			for _, e := range registered {
+				if e.mediaType == accepted ||
+					strings.HasSuffix(accepted, "/*") && strings.HasPrefix(e.mediaType, strings.TrimSuffix(accepted, "*")) {
+					return e
+				}
+			}
+		}
+	}
+
+	for _, e := range registered {
+		if e.mediaType == "application/json" {
+			return e
+		}
+	}
+
+	return encoding{"application/json", "application/json; charset=utf-8", encodeJSON}
+}
+
+// encodeJSON renders a body as JSON
+func encodeJSON(body *Body) ([]byte, error) {
+	data, err := json.Marshal(body)
+	return append(data, '\n'), err
+}
+
+// encodeProblem renders a body as an RFC 7807 problem
+func encodeProblem(body *Body) ([]byte, error) {
+	data, err := json.Marshal(&problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(body.Status),
+		Status:  body.Status,
+		Detail:  body.Message,
+		Code:    body.Code,
+		Details: body.Details,
+		ID:      body.ID,
+	})
+	return append(data, '\n'), err
+}
+
+// encodeXML renders a body as an XML error element
+func encodeXML(body *Body) ([]byte, error) {
+	// A details element only when there are details
+	type details struct {
+		Detail []interface{} `xml:"detail"`
+	}
+	e := struct {
+		XMLName xml.Name `xml:"error"`
+		*Body
+		Details *details `xml:"details,omitempty"`
+	}{Body: body}
+	if len(body.Details) > 0 {
+		e.Details = &details{body.Details}
+	}
+
+	data, err := xml.Marshal(e)
+	return append([]byte(xml.Header), data...), err
+}
+
+// encodeText renders a body as a line of text, followed by the error
+// message and the stack trace when they are exposed
+func encodeText(body *Body) ([]byte, error) {
+	line := fmt.Sprintf("%d %s", body.Status, body.Message)
+	if body.Code != "" {
+		line = fmt.Sprintf("%d %s: %s", body.Status, body.Code, body.Message)
+	}
+	if body.ID != "" {
+		line += fmt.Sprintf(" (error ID %s)", body.ID)
+	}
+
+	text := line + "\n"
+	if body.Error != "" {
+		text += body.Error + "\n"
+	}
+	if body.Stack != "" {
+		text += strings.TrimPrefix(body.Stack, "\n")
+	}
+
+	return []byte(text), nil
+}
@@ -0,0 +1,90 @@
+package weberr
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Content negotiation logic tested:
+// JSON is the default, for missing, wildcard and unsupported Accept headers
+// The preferred supported media type is used, ranges included
+// Details that can't be encoded are dropped
+func TestWriteErrorNegotiation(t *testing.T) {
+	err := SetCode(NotFound.UserErrorf("Order <42> not found"), "orders.NOT_FOUND")
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", "application/json; charset=utf-8", `{"status":404,"code":"orders.NOT_FOUND","message":"Order \u003c42\u003e not found"}` + "\n"},
+		{"*/*", "application/json; charset=utf-8", ""},
+		{"image/png", "application/json; charset=utf-8", ""},
+		{"text/html, application/json;q=0.5, text/plain;q=0.9", "text/plain; charset=utf-8", "404 orders.NOT_FOUND: Order <42> not found\n"},
+		{"TEXT/*", "text/plain; charset=utf-8", ""},
+		{"application/xml", "application/xml; charset=utf-8",
+			xml.Header + "<error><status>404</status><code>orders.NOT_FOUND</code><message>Order &lt;42&gt; not found</message></error>"},
+		{"application/problem+json", "application/problem+json",
+			`{"type":"about:blank","title":"Not Found","status":404,"detail":"Order \u003c42\u003e not found","code":"orders.NOT_FOUND"}` + "\n"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", tt.accept)
+
+		rec := httptest.NewRecorder()
+		WriteError(rec, r, err)
+
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%q: got: %q, want %q", tt.accept, got, tt.contentType)
+		}
+		if tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("%q: got: %q, want %q", tt.accept, rec.Body.String(), tt.body)
+		}
+		if rec.Code != 404 || rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("%q: got: %d %v", tt.accept, rec.Code, rec.Header())
+		}
+	}
+
+	// XML can't encode maps
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	wr := Writer{Exposure: ExposeDetails}
+	wr.WriteError(rec, r, AddDetails(err, map[string]string{"id": "42"}))
+	if got := rec.Body.String(); !strings.Contains(got, "<message>") || strings.Contains(got, "<details>") {
+		t.Errorf("got: %q, want the body without details", got)
+	}
+
+	// Text has the error message and the stack trace after the line
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	rec = httptest.NewRecorder()
+	wr = Writer{Exposure: ExposeAll}
+	wr.WriteError(rec, r, Wrapf(err, "loading"))
+	if got := rec.Body.String(); !strings.HasPrefix(got, "404 orders.NOT_FOUND: Order <42> not found\nloading: Order <42> not found\ntesting.tRunner\n") {
+		t.Errorf("got: %q, want the line, the error message and the stack trace", got)
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	defer RegisterEncoder("text/plain; charset=utf-8", encodeText)
+
+	RegisterEncoder("text/plain; charset=us-ascii", func(body *Body) ([]byte, error) {
+		return []byte(body.Message), nil
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	WriteError(rec, r, NotFound.UserErrorf("Not here"))
+
+	if rec.Header().Get("Content-Type") != "text/plain; charset=us-ascii" || rec.Body.String() != "Not here" {
+		t.Errorf("got: %v %q", rec.Header(), rec.Body.String())
+	}
+
+	if !panics(func() { RegisterEncoder("text/", encodeText) }) {
+		t.Errorf("expected a panic for an invalid content type")
+	}
+}
@@ -0,0 +1,97 @@
+package weberr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errorIDs makes response bodies include error IDs
+var errorIDs atomic.Bool
+
+// SetErrorIDs sets whether the bodies of error responses include the ID of
+// the error (see GetErrorID), so that users can report it. It is disabled
+// by default. Logs always include the ID.
+func SetErrorIDs(enabled bool) {
+	errorIDs.Store(enabled)
+}
+
+// GetErrorID returns the unique ID of an occurrence of an error, for users
+// to report and operators to find in the logs, e.g. "3f9c1a0b7d2e".
+// The ID belongs to the error created first by the package: the one of a
+// constructor such as Errorf, or the first one wrapping an error of another
+// package, e.g. Wrapf(io.EOF, ...) or UserWrapf(ErrQuotaExceeded, ...) for a
+// sentinel. Errors created from it share it, so that every layer of the
+// chain, logged or rendered, has the same ID, whichever is asked first.
+// Package-level *Error values are a single occurrence: declare sentinels
+// instead (see SentinelError), so that every error wrapping them has its own ID.
+// WithErrorID sets the ID instead.
+// It returns an empty ID if err isn't an error of the package.
+func GetErrorID(err error) string {
+	if id := errorIDOf(err); id != nil {
+		return id.String()
+	}
+
+	return ""
+}
+
+// errorID is the ID of an occurrence of an error, shared by the errors
+// created from it, and generated when it is first asked for
+type errorID struct {
+	once sync.Once
+	id   string
+}
+
+// String returns the ID, generating it if it isn't set
+func (e *errorID) String() string {
+	e.once.Do(func() {
+		if e.id == "" {
+			e.id = newErrorID()
+		}
+	})
+
+	return e.id
+}
+
+// errorIDOf returns the ID of the outermost error of the package in the
+// chain of err, which it holds from when an error is first created from it,
+// or nil if err isn't an error of the package
+func errorIDOf(err error) *errorID {
+	for _, e := range Chain(err) {
+		if c, ok := e.(*Error); ok {
+			if id := c.id.Load(); id != nil {
+				return id
+			}
+			c.id.CompareAndSwap(nil, &errorID{})
+			return c.id.Load()
+		}
+	}
+
+	return nil
+}
+
+// WithErrorID sets the ID of an error, e.g. to keep the ID of an error
+// received from another service.
+func WithErrorID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+
+	c := inherit(err)
+	c.id.Store(&errorID{id: id})
+
+	return c
+}
+
+// newErrorID returns a random error ID
+func newErrorID() string {
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,117 @@
+package weberr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Error ID logic tested:
+// IDs belong to the error created first, and are shared by the errors created from it
+// Different occurrences get different IDs, even wrapping a sentinel
+// WithErrorID sets the ID
+func TestGetErrorID(t *testing.T) {
+	err := NotFound.UserErrorf("Not here")
+	id := GetErrorID(err)
+	if !regexp.MustCompile(`^[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("got: %q, want 12 hex digits", id)
+	}
+
+	for _, wrapped := range []error{err, Wrapf(err, "msg"), UserWrapf(err, "msg"), SetCode(err, "A"), WithRetryAfter(err, 1)} {
+		if got := GetErrorID(wrapped); got != id {
+			t.Errorf("%v: got: %q, want %q", wrapped, got, id)
+		}
+	}
+
+	// The wrapper asked first has the ID of the error it wraps
+	inner := Errorf("msg")
+	outer := Wrapf(fmt.Errorf("reading: %w", inner), "msg")
+	if GetErrorID(outer) != GetErrorID(inner) {
+		t.Errorf("got: %q and %q, want the same ID", GetErrorID(outer), GetErrorID(inner))
+	}
+	if GetErrorID(Wrapf(outer, "msg")) != GetErrorID(outer) {
+		t.Errorf("expected later wrappers to share the ID")
+	}
+
+	if GetErrorID(NotFound.UserErrorf("Not here")) == id {
+		t.Errorf("expected different occurrences to have different IDs")
+	}
+
+	set := WithErrorID(Wrapf(err, "msg"), "abc123")
+	if GetErrorID(set) != "abc123" || GetErrorID(Wrapf(set, "msg")) != "abc123" || GetErrorID(err) != id {
+		t.Errorf("got: %q, want abc123", GetErrorID(set))
+	}
+
+	// Every occurrence wrapping a sentinel has its own ID
+	first, second := Wrapf(errOrderNotFound, "request 1"), Wrapf(errOrderNotFound, "request 2")
+	if GetErrorID(first) == GetErrorID(second) {
+		t.Errorf("got: %q for both occurrences, want different IDs", GetErrorID(first))
+	}
+	if GetErrorID(Wrapf(first, "msg")) != GetErrorID(first) || GetErrorID(errOrderNotFound) != "" {
+		t.Errorf("expected the IDs of the occurrences only")
+	}
+
+	if GetErrorID(io.EOF) != "" || GetErrorID(nil) != "" || WithErrorID(nil, "id") != nil {
+		t.Errorf("expected no ID")
+	}
+}
+
+// errOrderNotFound is a sentinel, wrapped by every occurrence
+var errOrderNotFound = NotFound.Sentinel("orders.ORDER_NOT_FOUND")
+
+// The errors of the hooks have the ID the error is logged with, wherever it
+// is requested first
+func TestErrorIDHooks(t *testing.T) {
+	defer resetHooks()
+	Use(func(err error) error { return SetCode(err, "A") })
+
+	err := Wrapf(errOrderNotFound, "loading order")
+	presented := Present(err)
+	if GetErrorID(presented) != GetErrorID(err) || GetCode(presented) != "A" {
+		t.Errorf("got: %q, want %q", GetErrorID(presented), GetErrorID(err))
+	}
+}
+
+func TestErrorIDResponse(t *testing.T) {
+	defer SetErrorIDs(false)
+	err := WithErrorID(NotFound.UserErrorf("Not here"), "abc123")
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, nil, err)
+	if strings.Contains(rec.Body.String(), "abc123") {
+		t.Errorf("got: %s, want no ID by default", rec.Body.String())
+	}
+
+	SetErrorIDs(true)
+	rec = httptest.NewRecorder()
+	WriteError(rec, nil, err)
+	var body Body
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.ID != "abc123" {
+		t.Errorf("got: %s, want the ID", rec.Body.String())
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	rec = httptest.NewRecorder()
+	WriteError(rec, r, err)
+	if got := rec.Body.String(); got != "404 Not here (error ID abc123)\n" {
+		t.Errorf("got: %q", got)
+	}
+
+	// The ID is kept by the services decoding the error
+	decoded, _ := ToJSON(err)
+	if got := GetErrorID(FromJSON(decoded)); got != "abc123" {
+		t.Errorf("got: %q, want abc123", got)
+	}
+
+	r.Header.Set("Accept", "application/problem+json")
+	rec = httptest.NewRecorder()
+	WriteError(rec, r, err)
+	if got := GetErrorID(FromResponse(rec.Result())); got != "abc123" {
+		t.Errorf("got: %q from %s, want abc123", got, rec.Body.String())
+	}
+}
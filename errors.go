@@ -2,7 +2,11 @@ package weberr
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -99,14 +103,102 @@ const (
 	NotExtended ErrorType = http.StatusNotExtended
 	// NetworkAuthenticationRequired error - Code 511
 	NetworkAuthenticationRequired ErrorType = http.StatusNetworkAuthenticationRequired
+
+	// Aliases
+	// -------
+
+	// Internal error - Code 500, alias of InternalServerError
+	Internal = InternalServerError
+	// Timeout error - Code 504, alias of GatewayTimeout, for operations
+	// that timed out waiting for another service
+	Timeout = GatewayTimeout
 )
 
-// customError wraps an error with type, separate user message, and details.
-type customError struct {
+// Error wraps an error with type, separate user message, and details.
+// Errors are created by the functions of the package, and are read-only:
+// their methods give access to all their attributes at once, e.g. in
+// middlewares handling them generically:
+//
+//	var webErr *weberr.Error
+//	if errors.As(err, &webErr) {
+//		log.Printf("%d %s: %s", webErr.Type(), webErr.Code(), webErr.UserMessage())
+//	}
+//
+// Unlike the accessors of the package (GetType, GetUserMessage...), the
+// methods don't fall back to defaults, e.g. Severity returns NoSeverity
+// unless a severity was set. GetStackTrace returns the stack trace.
+type Error struct {
 	error
 	errorType   ErrorType
 	userMessage string
+	code        Code
+	severity    Severity
+	retryAfter  time.Duration
+	headers     http.Header
 	details     []interface{}
+
+	// messageKey and messageArgs allow localizing the user message at render time
+	messageKey  string
+	messageArgs []interface{}
+
+	// messageTemplate and templateArgs allow rendering the user message again
+	messageTemplate string
+	templateArgs    []TemplateArg
+
+	// wrapped is where the error was wrapped, for GetTrace
+	wrapped *wrapSite
+
+	// id is the ID of the occurrence of the error, shared with the errors
+	// created from it (see GetErrorID)
+	id atomic.Pointer[errorID]
+}
+
+// wrapping is an Error with the message it adds to the wrapped error and
+// where it was wrapped, allocated at once by Wrapf and UserWrapf
+type wrapping struct {
+	Error
+	layer messageError
+	site  wrapSite
+}
+
+// messageError adds a message to the error it wraps, as errors.WithMessage
+type messageError struct {
+	cause error
+	msg   string
+}
+
+func (w *messageError) Error() string { return w.msg + ": " + w.cause.Error() }
+
+// Cause unwraps error
+func (w *messageError) Cause() error { return w.cause }
+
+// Unwrap unwraps error
+func (w *messageError) Unwrap() error { return w.cause }
+
+// Format formats the error as errors.WithMessage does:
+// %+v shows the wrapped error with its stack trace, then the message.
+func (w *messageError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v\n", w.cause)
+			io.WriteString(s, w.msg)
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		io.WriteString(s, w.Error())
+	}
+}
+
+// sprintf formats msg with args, without allocating for messages that
+// don't need formatting
+func sprintf(msg string, args ...interface{}) string {
+	if len(args) == 0 && strings.IndexByte(msg, '%') < 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
 }
 
 // causer interface allows unwrapping an error.
@@ -116,7 +208,11 @@ type causer interface {
 }
 
 // Cause unwraps error
-func (c *customError) Cause() error { return c.error }
+func (c *Error) Cause() error { return c.error }
+
+// Unwrap unwraps error as Cause does, so that errors.Is and errors.As of the
+// standard library reach the wrapped errors, e.g. a net.Error.
+func (c *Error) Unwrap() error { return c.error }
 
 // typed interface identifies error with a type
 type typed interface {
@@ -124,7 +220,7 @@ type typed interface {
 }
 
 // Type returns the error type
-func (c *customError) Type() ErrorType { return c.errorType }
+func (c *Error) Type() ErrorType { return c.errorType }
 
 // GetType returns the error type for all errors.
 // If error is not `typed` - it returns NoType.
@@ -142,7 +238,7 @@ type userMessager interface {
 }
 
 // UserMessage returns the user message
-func (c *customError) UserMessage() string { return c.userMessage }
+func (c *Error) UserMessage() string { return c.userMessage }
 
 // GetUserMessage returns user readable error message for all errors.
 // If error is not `userMessager` returns empty string.
@@ -160,7 +256,7 @@ type errorDetailer interface {
 }
 
 // Details returns the error details
-func (c *customError) Details() []interface{} { return c.details }
+func (c *Error) Details() []interface{} { return c.details }
 
 // GetDetails returns a slice of arbitrary details for all errors.
 // If error is not `errorDetailer` returns nil.
@@ -174,8 +270,21 @@ func GetDetails(err error) []interface{} {
 
 // Errorf creates a new error of this type with formatted string.
 func (errorType ErrorType) Errorf(msg string, args ...interface{}) error {
-	return &customError{
-		error:     errors.WithStack(errors.Errorf(msg, args...)),
+	err := plainErrorf(msg, args...)
+	if captureStack(errorType) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:     err,
+		errorType: errorType,
+	}
+}
+
+// ErrorfNoStack creates a new error of this type with formatted string, without a stack trace.
+func (errorType ErrorType) ErrorfNoStack(msg string, args ...interface{}) error {
+	return &Error{
+		error:     plainErrorf(msg, args...),
 		errorType: errorType,
 	}
 }
@@ -188,15 +297,25 @@ func (errorType ErrorType) Wrapf(err error, msg string, args ...interface{}) err
 		return errorType.Errorf(msg, args...)
 	}
 
-	c := new(customError)
-	c.error = errors.Wrapf(err, msg, args...)
-	c.userMessage = GetUserMessage(err)
-	c.details = GetDetails(err)
-
+	// A single allocation for the error, its message and its wrapping site
+	w := new(wrapping)
+	c := &w.Error
+	c.copyAttributes(err)
 	if errorType != NoType {
 		c.errorType = errorType
+	}
+
+	message := sprintf(msg, args...)
+	w.layer = messageError{cause: err, msg: message}
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(&w.layer)
 	} else {
-		c.errorType = GetType(err)
+		c.error = &w.layer
+	}
+	if captureStack(c.errorType) {
+		w.site.message = message
+		w.site.record()
+		c.wrapped = &w.site
 	}
 
 	return c
@@ -211,22 +330,32 @@ func (errorType ErrorType) UserWrapf(err error, msg string, args ...interface{})
 		return errorType.UserErrorf(msg, args...)
 	}
 
-	userMsg := fmt.Sprintf(msg, args...)
-
-	c := new(customError)
-	c.error = errors.WithStack(err)
-	c.details = GetDetails(err)
-
-	origMsg := GetUserMessage(err)
-	if origMsg != "" {
-		userMsg = fmt.Sprintf("%s: %s", userMsg, origMsg)
-	}
-	c.userMessage = userMsg
+	userMsg := sprintf(msg, args...)
 
+	w := new(wrapping)
+	c := &w.Error
+	c.copyAttributes(err)
 	if errorType != NoType {
 		c.errorType = errorType
+	}
+
+	// The user message changes, its key and template no longer apply
+	if origMsg := c.userMessage; origMsg != "" {
+		c.userMessage = userMsg + ": " + origMsg
 	} else {
-		c.errorType = GetType(err)
+		c.userMessage = userMsg
+	}
+	c.messageKey, c.messageArgs = "", nil
+	c.messageTemplate, c.templateArgs = "", nil
+
+	c.error = err
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+	if captureStack(c.errorType) {
+		w.site.userMessage = userMsg
+		w.site.record()
+		c.wrapped = &w.site
 	}
 
 	return c
@@ -235,8 +364,24 @@ func (errorType ErrorType) UserWrapf(err error, msg string, args ...interface{})
 // UserErrorf creates a new error with a user readable message.
 func (errorType ErrorType) UserErrorf(msg string, args ...interface{}) error {
 	message := fmt.Sprintf(msg, args...)
-	return &customError{
-		error:       errors.WithStack(errors.New(message)),
+
+	err := plainErrorf("%s", message)
+	if captureStack(errorType) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:       err,
+		errorType:   errorType,
+		userMessage: message,
+	}
+}
+
+// UserErrorfNoStack creates a new error with a user readable message, without a stack trace.
+func (errorType ErrorType) UserErrorfNoStack(msg string, args ...interface{}) error {
+	message := fmt.Sprintf(msg, args...)
+	return &Error{
+		error:       plainErrorf("%s", message),
 		errorType:   errorType,
 		userMessage: message,
 	}
@@ -253,16 +398,14 @@ func (errorType ErrorType) AddDetails(err error, details interface{}) error {
 		return errorType.details(details)
 	}
 
-	c := new(customError)
-	c.error = errors.WithStack(err)
-	c.userMessage = GetUserMessage(err)
-
-	c.details = append(GetDetails(err), details)
-
+	c := inherit(err)
+	c.details = append(c.details, details)
 	if errorType != NoType {
 		c.errorType = errorType
-	} else {
-		c.errorType = GetType(err)
+	}
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
 	}
 
 	return c
@@ -270,8 +413,13 @@ func (errorType ErrorType) AddDetails(err error, details interface{}) error {
 
 // details creates a new error with arbitrary details
 func (errorType ErrorType) details(details interface{}) error {
-	return &customError{
-		error:     errors.WithStack(errors.New("")),
+	err := plainErrorf("")
+	if captureStack(errorType) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:     err,
 		errorType: errorType,
 		details:   []interface{}{details},
 	}
@@ -283,12 +431,14 @@ func (errorType ErrorType) Set(err error) error {
 		return nil
 	}
 
-	return &customError{
-		error:       errors.WithStack(err),
-		errorType:   errorType,
-		userMessage: GetUserMessage(err),
-		details:     GetDetails(err),
+	c := inherit(err)
+	c.errorType = errorType
+
+	if needsStack(errorType, err) {
+		c.error = errors.WithStack(err)
 	}
+
+	return c
 }
 
 func (errorType ErrorType) SetUserMessage(err error, msg string) error {
@@ -296,19 +446,21 @@ func (errorType ErrorType) SetUserMessage(err error, msg string) error {
 		return nil
 	}
 
-	var newType ErrorType
+	c := inherit(err)
 	if errorType != NoType {
-		newType = errorType
-	} else {
-		newType = GetType(err)
+		c.errorType = errorType
 	}
 
-	return &customError{
-		error:       errors.WithStack(err),
-		errorType:   newType,
-		userMessage: msg,
-		details:     GetDetails(err),
+	// The user message changes, its key and template no longer apply
+	c.userMessage = msg
+	c.messageKey, c.messageArgs = "", nil
+	c.messageTemplate, c.templateArgs = "", nil
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
 	}
+
+	return c
 }
 
 // Errorf returns a new NoType error with formatted string.
@@ -367,21 +519,26 @@ func baseStackTracer(err error) error {
 }
 
 // GetStackTrace returns the stack trace starting from the first error
-// that has been wrapped / created
+// that has been wrapped / created.
+// Frames are filtered and limited as set by SetStackFrameFilter and
+// SetMaxStackDepth.
 func GetStackTrace(err error) string {
 	if err == nil {
 		return ""
 	}
 
-	err = baseStackTracer(err)
-	x, ok := err.(stackTracer)
+	x, ok := baseStackTracer(err).(stackTracer)
 	if !ok {
 		// The error doesn't have a stack trace attached to it
 		return fmt.Sprintf("%+v", err)
 	}
 
-	st := x.StackTrace()
-	return fmt.Sprintf("%+v\n", st[1:])
+	st := filterStack(trimStack(x.StackTrace()))
+	if len(st) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%+v\n", st)
 }
 
 // As finds the first error in err's chain that matches target,
@@ -0,0 +1,103 @@
+// Package errors is a drop-in replacement for github.com/pkg/errors that
+// creates weberr errors, so that a codebase can switch to weberr by only
+// changing its imports:
+//
+//	import "github.com/pkg/errors"
+//
+// becomes
+//
+//	import "github.com/zgalor/weberr/errors"
+//
+// and then adopt types and user messages gradually, with the functions of
+// package weberr.
+//
+// The functions have the signatures and the nil handling of
+// github.com/pkg/errors: wrapping nil returns nil. Unlike in
+// github.com/pkg/errors, wrapping an error that has a stack trace doesn't
+// record another one, and WithMessage records one if the error has none.
+package errors
+
+import (
+	stderrors "errors"
+
+	"github.com/pkg/errors"
+	"github.com/zgalor/weberr"
+)
+
+// Frame is a frame of a stack trace.
+type Frame = errors.Frame
+
+// StackTrace is a stack trace, outermost frame first.
+type StackTrace = errors.StackTrace
+
+// New returns an error with message and a stack trace.
+func New(message string) error {
+	return weberr.Errorf("%s", message)
+}
+
+// Errorf returns an error with a formatted message and a stack trace.
+func Errorf(format string, args ...interface{}) error {
+	return weberr.Errorf(format, args...)
+}
+
+// WithStack adds a stack trace to err, or returns nil if err is nil.
+func WithStack(err error) error {
+	return weberr.WithStack(err)
+}
+
+// Wrap adds message to err, or returns nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return weberr.Wrapf(err, "%s", message)
+}
+
+// Wrapf adds a formatted message to err, or returns nil if err is nil.
+func Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return weberr.Wrapf(err, format, args...)
+}
+
+// WithMessage adds message to err, or returns nil if err is nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return weberr.Wrapf(err, "%s", message)
+}
+
+// WithMessagef adds a formatted message to err, or returns nil if err is nil.
+func WithMessagef(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return weberr.Wrapf(err, format, args...)
+}
+
+// Cause returns the innermost error of the chain of err unwrapped with
+// Cause, or err itself if it doesn't implement Cause.
+func Cause(err error) error {
+	return errors.Cause(err)
+}
+
+// Is reports whether an error of the chain of err matches target, as errors.Is.
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error of the chain of err that matches target, as errors.As.
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+// Unwrap returns the error wrapped by err, as errors.Unwrap.
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/zgalor/weberr"
+	"github.com/zgalor/weberr/errors"
+)
+
+// Shim logic tested:
+// Functions produce weberr errors with the messages of github.com/pkg/errors
+// Wrapping nil returns nil
+// Causes and stack traces are found as with github.com/pkg/errors
+func TestShim(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{errors.New("not found"), pkgerrors.New("not found")},
+		{errors.New("100%"), pkgerrors.New("100%")},
+		{errors.Errorf("order %d", 42), pkgerrors.Errorf("order %d", 42)},
+		{errors.WithStack(io.EOF), pkgerrors.WithStack(io.EOF)},
+		{errors.Wrap(io.EOF, "reading 100%"), pkgerrors.Wrap(io.EOF, "reading 100%")},
+		{errors.Wrapf(io.EOF, "reading %d", 42), pkgerrors.Wrapf(io.EOF, "reading %d", 42)},
+		{errors.WithMessage(io.EOF, "reading"), pkgerrors.WithMessage(io.EOF, "reading")},
+		{errors.WithMessagef(io.EOF, "reading %d", 42), pkgerrors.WithMessagef(io.EOF, "reading %d", 42)},
+	}
+	for _, tt := range tests {
+		if tt.err.Error() != tt.expected.Error() {
+			t.Errorf("got: %q, want %q", tt.err, tt.expected)
+		}
+		if _, ok := tt.err.(*weberr.Error); !ok {
+			t.Errorf("%v: got: %T, want a weberr error", tt.err, tt.err)
+		}
+		// Stack traces start at the caller, as with github.com/pkg/errors
+		if frames := weberr.GetStackFrames(tt.err); len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "errors_test.TestShim") {
+			t.Errorf("%v: got: %+v, want a stack trace starting at the test", tt.err, frames)
+		}
+		if _, _, fn := weberr.Origin(tt.err); !strings.HasSuffix(fn, "errors_test.TestShim") {
+			t.Errorf("%v: got: origin %q, want the test", tt.err, fn)
+		}
+	}
+
+	for _, err := range []error{errors.WithStack(nil), errors.Wrap(nil, "msg"), errors.Wrapf(nil, "msg"), errors.WithMessage(nil, "msg"), errors.WithMessagef(nil, "msg")} {
+		if err != nil {
+			t.Errorf("got: %v, want nil", err)
+		}
+	}
+
+	err := errors.Wrap(fmt.Errorf("decoding: %w", io.EOF), "reading")
+	if errors.Cause(err).Error() != "decoding: EOF" || !errors.Is(err, io.EOF) || errors.Unwrap(err) == nil {
+		t.Errorf("got: cause %v", errors.Cause(err))
+	}
+
+	var st interface{ StackTrace() errors.StackTrace }
+	if !errors.As(pkgerrors.WithStack(err), &st) || len(st.StackTrace()) == 0 {
+		t.Errorf("expected a stack tracer")
+	}
+
+	if trace := weberr.GetTrace(errors.Wrap(weberr.Errorf("msg"), "loading")); !strings.HasPrefix(trace, "wrapped at errors_test.go:") ||
+		!strings.Contains(trace, "(errors_test.TestShim): loading") {
+		t.Errorf("got: %q, want the test as wrapping site", trace)
+	}
+
+	typed := errors.Wrap(weberr.NotFound.UserErrorf("Not here"), "loading")
+	if weberr.GetType(typed) != weberr.NotFound || weberr.GetUserMessage(typed) != "Not here" {
+		t.Errorf("got: %v %q, want the attributes of the wrapped error", weberr.GetType(typed), weberr.GetUserMessage(typed))
+	}
+}
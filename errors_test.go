@@ -1,9 +1,14 @@
 package weberr
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
 )
 
 // TestGetStackTrace tests that the stack always starts with the root cause
@@ -167,6 +172,23 @@ func TestGetDetails(t *testing.T) {
 
 }
 
+func TestAsError(t *testing.T) {
+	err := SetCode(AddDetails(NotFound.UserWrapf(io.EOF, "Not here"), "foo"), "A")
+
+	var target *Error
+	if !errors.As(fmt.Errorf("handler: %w", err), &target) {
+		t.Fatalf("expected %v to be an *Error", err)
+	}
+	if target.Type() != NotFound || target.UserMessage() != "Not here" || target.Code() != "A" ||
+		!compare(target.Details(), []interface{}{"foo"}) || target.Error() != "EOF" {
+		t.Errorf("got: %v %q %q %v %q", target.Type(), target.UserMessage(), target.Code(), target.Details(), target)
+	}
+
+	if errors.As(fmt.Errorf("handler: %w", io.EOF), &target) {
+		t.Errorf("expected io.EOF not to be an *Error")
+	}
+}
+
 // Helper function to compare slices
 func compare(a, b []interface{}) bool {
 	if len(a) != len(b) {
@@ -179,3 +201,71 @@ func compare(a, b []interface{}) bool {
 	}
 	return true
 }
+
+// timeoutError is a net.Error
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// TestUnwrapPassthrough tests that the errors wrapped by all the constructors
+// are found by errors.Is and errors.As
+func TestUnwrapPassthrough(t *testing.T) {
+	var cause error = timeoutError{}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"Wrapf", Wrapf(cause, "msg")},
+		{"ErrorType.Wrapf", GatewayTimeout.Wrapf(cause, "msg")},
+		{"UserWrapf", UserWrapf(cause, "msg")},
+		{"ErrorType.UserWrapf", GatewayTimeout.UserWrapf(cause, "msg")},
+		{"AddDetails", AddDetails(cause, "detail")},
+		{"Set", GatewayTimeout.Set(cause)},
+		{"SetUserMessage", SetUserMessage(cause, "msg")},
+		{"SetCode", SetCode(cause, "A")},
+		{"WithSeverity", WithSeverity(cause, SeverityWarning)},
+		{"WithRetryAfter", WithRetryAfter(cause, time.Second)},
+		{"WithHeader", WithHeader(cause, "X-Upstream", "db")},
+		{"Builder", New("msg").Wrap(cause).Type(GatewayTimeout).Err()},
+		{"Join", Join(NotFound.Errorf("msg"), cause)},
+		{"nested", SetCode(UserWrapf(Wrapf(fmt.Errorf("dial: %w", cause), "msg"), "user msg"), "A")},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, cause) {
+			t.Errorf("%s: expected errors.Is to find the cause of %v", tt.name, tt.err)
+		}
+
+		var netErr net.Error
+		if !errors.As(tt.err, &netErr) || !netErr.Timeout() {
+			t.Errorf("%s: expected errors.As to find the net.Error of %v", tt.name, tt.err)
+		}
+		if !As(tt.err, &netErr) {
+			t.Errorf("%s: expected As to find the net.Error of %v", tt.name, tt.err)
+		}
+	}
+
+	if errors.Is(Wrapf(io.EOF, "msg"), cause) {
+		t.Errorf("expected errors.Is not to find an unrelated error")
+	}
+}
+
+// TestWrapfFormat tests that Wrapf layers format as the ones of github.com/pkg/errors
+func TestWrapfFormat(t *testing.T) {
+	defer SetStackCapture(NoType, true)
+	SetStackCapture(NoType, false)
+
+	err := Wrapf(io.EOF, "reading order %d", 42).(*Error).Cause()
+	expected := pkgerrors.WithMessage(io.EOF, "reading order 42")
+
+	for _, format := range []string{"%s", "%v", "%q", "%+v"} {
+		if got, want := fmt.Sprintf(format, err), fmt.Sprintf(format, expected); got != want {
+			t.Errorf("%s: got: %q, want %q", format, got, want)
+		}
+	}
+	if pkgerrors.Cause(err) != io.EOF {
+		t.Errorf("got: %v, want %v", pkgerrors.Cause(err), io.EOF)
+	}
+}
@@ -0,0 +1,27 @@
+// Command orders is an example service using weberr: it returns typed
+// errors with codes from a translated catalog, validation errors, recovered
+// panics and load shedding 503s, and serves the catalog at /errors.
+//
+//	go run ./examples/orders -addr :8080
+//	curl -H 'Accept-Language: fr' localhost:8080/orders/42
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/zgalor/weberr"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxInFlight := flag.Int("max-in-flight", 100, "requests served concurrently")
+	maxQueue := flag.Int("max-queue", 100, "requests waiting to be served")
+	flag.Parse()
+
+	handler := newServer(&weberr.Overload{MaxInFlight: *maxInFlight, MaxQueue: *maxQueue})
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/zgalor/weberr"
+	"github.com/zgalor/weberr/catalog"
+	"github.com/zgalor/weberr/validation"
+)
+
+var ns = weberr.NewNamespace("orders")
+
+// Codes of the errors of the service
+var (
+	codeNotFound = ns.Code("ORDER_NOT_FOUND")
+	codeExists   = ns.Code("ORDER_EXISTS")
+)
+
+// createOrder is the body of order creation requests
+type createOrder struct {
+	ID   string `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+// order is an order of the store
+type order struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func init() {
+	weberr.DefaultCatalog = weberr.NewCatalog("en", "fr")
+	weberr.Register(
+		weberr.CatalogEntry{Code: codeNotFound, Type: weberr.NotFound, Message: "Order %s not found",
+			Translations: map[string]string{"fr": "Commande %s introuvable"}},
+		weberr.CatalogEntry{Code: codeExists, Type: weberr.Conflict, Message: "Order %s already exists",
+			Translations: map[string]string{"fr": "La commande %s existe déjà"}},
+	)
+	validation.Register("orders", createOrder{})
+
+	weberr.MustValidateCatalog()
+}
+
+// server serves the orders API
+type server struct {
+	mu     sync.Mutex
+	orders map[string]order
+
+	writer *weberr.Writer
+}
+
+// newServer returns the handler of the API, limited by overload.
+// The errors of overload are written like the others, unless it has a writer.
+func newServer(overload *weberr.Overload) http.Handler {
+	s := &server{
+		orders: map[string]order{},
+		writer: &weberr.Writer{Exposure: weberr.ExposeDetails},
+	}
+	if overload.Writer == nil {
+		overload.Writer = s.writer
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/orders", s.handle(s.create))
+	mux.Handle("/orders/", s.handle(s.get))
+	mux.Handle("/errors", s.handle(s.listErrors))
+	mux.Handle("/panic", s.handle(s.crash))
+
+	return s.writer.Recover(overload.Handler(mux))
+}
+
+// handle returns a handler writing the errors of h
+func (s *server) handle(h func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			s.writer.WriteError(w, r, err)
+		}
+	})
+}
+
+// create serves POST /orders
+func (s *server) create(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return weberr.MethodNotAllowed.UserErrorfNoStack("Only POST is supported")
+	}
+
+	var req createOrder
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return weberr.BadRequest.UserWrapf(err, "The request body is not valid JSON")
+	}
+	switch {
+	case req.ID == "":
+		return validation.FieldError("orders", "createOrder", "id", "required")
+	case req.Name == "":
+		return validation.FieldError("orders", "createOrder", "name", "required")
+	case len(req.Name) > 64:
+		return validation.FieldError("orders", "createOrder", "name", "max")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[req.ID]; ok {
+		return weberr.UserErrorfKey(string(codeExists), req.ID)
+	}
+	s.orders[req.ID] = order(req)
+
+	return writeJSON(w, http.StatusCreated, s.orders[req.ID])
+}
+
+// get serves GET /orders/{id}
+func (s *server) get(w http.ResponseWriter, r *http.Request) error {
+	id := strings.TrimPrefix(r.URL.Path, "/orders/")
+
+	s.mu.Lock()
+	o, ok := s.orders[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return weberr.UserErrorfKey(string(codeNotFound), id)
+	}
+
+	return writeJSON(w, http.StatusOK, o)
+}
+
+// listErrors serves GET /errors, the catalog of the errors of the API
+func (s *server) listErrors(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return catalog.WriteJSON(w, weberr.DefaultCatalog)
+}
+
+// crash serves GET /panic, which fails on a nil map
+func (s *server) crash(w http.ResponseWriter, r *http.Request) error {
+	var counts map[string]int
+	counts[r.URL.Path]++
+
+	return nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return weberr.Wrapf(err, "encoding response")
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zgalor/weberr"
+	"github.com/zgalor/weberr/catalog"
+)
+
+// request sends a request to the server and returns the response, and the
+// error decoded from it
+func request(t *testing.T, srv *httptest.Server, method, path, body string, header ...string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp, weberr.FromResponse(resp)
+}
+
+// Orders API logic tested end-to-end:
+// Catalog errors carry their code, and are translated with Accept-Language
+// Validation errors carry their rule code and field details
+// Malformed bodies are BadRequest, with the user message only
+// Panics are InternalServerError without leaking the panic
+// The catalog is served at /errors
+func TestOrders(t *testing.T) {
+	srv := httptest.NewServer(newServer(&weberr.Overload{MaxInFlight: 10, MaxQueue: 10}))
+	defer srv.Close()
+
+	resp, err := request(t, srv, "POST", "/orders", `{"id":"42","name":"Coffee"}`)
+	if resp.StatusCode != http.StatusCreated || err != nil {
+		t.Fatalf("got: %d %v, want %d", resp.StatusCode, err, http.StatusCreated)
+	}
+
+	tests := []struct {
+		method  string
+		path    string
+		body    string
+		locale  string
+		errType weberr.ErrorType
+		code    weberr.Code
+		userMsg string
+		details int
+	}{
+		{"GET", "/orders/42", "", "", weberr.NoType, "", "", 0},
+		{"GET", "/orders/7", "", "", weberr.NotFound, codeNotFound, "Order 7 not found", 0},
+		{"GET", "/orders/7", "", "fr-CA,en;q=0.5", weberr.NotFound, codeNotFound, "Commande 7 introuvable", 0},
+		{"POST", "/orders", `{"id":"42","name":"Tea"}`, "fr", weberr.Conflict, codeExists, "La commande 42 existe déjà", 0},
+		{"POST", "/orders", `{"id":"43"}`, "", weberr.BadRequest, "orders.createOrder.name.required", "name is required", 1},
+		{"POST", "/orders", `{"id":"43","name":"` + strings.Repeat("a", 65) + `"}`, "", weberr.BadRequest,
+			"orders.createOrder.name.max", "name must be at most 64 characters long", 1},
+		{"POST", "/orders", `{"id":`, "", weberr.BadRequest, "", "The request body is not valid JSON", 0},
+		{"DELETE", "/orders", "", "", weberr.MethodNotAllowed, "", "Only POST is supported", 0},
+		{"GET", "/panic", "", "", weberr.InternalServerError, "", "Internal Server Error", 0},
+	}
+	for _, tt := range tests {
+		resp, err := request(t, srv, tt.method, tt.path, tt.body, "Accept-Language", tt.locale)
+		if tt.errType == weberr.NoType {
+			if err != nil {
+				t.Errorf("%s %s: got: %v, want no error", tt.method, tt.path, err)
+			}
+			continue
+		}
+
+		if resp.StatusCode != tt.errType.HTTPStatus() || weberr.GetType(err) != tt.errType || weberr.GetCode(err) != tt.code ||
+			weberr.GetUserMessage(err) != tt.userMsg || len(weberr.GetDetails(err)) != tt.details {
+			t.Errorf("%s %s: got: %d %v %q %q %v, want %v %q %q %d details", tt.method, tt.path, resp.StatusCode,
+				weberr.GetType(err), weberr.GetCode(err), weberr.GetUserMessage(err), weberr.GetDetails(err),
+				tt.errType, tt.code, tt.userMsg, tt.details)
+		}
+		if strings.Contains(err.Error(), "nil map") || strings.Contains(err.Error(), "invalid character") {
+			t.Errorf("%s %s: got: internal error %q", tt.method, tt.path, err)
+		}
+	}
+
+	resp, _ = request(t, srv, "GET", "/errors", "")
+	var doc catalog.Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Errors) != len(weberr.DefaultCatalog.Entries()) || doc.Errors[0].Code != codeNotFound || doc.Errors[0].Status != 404 {
+		t.Errorf("got: %+v", doc)
+	}
+}
+
+// Requests over the limits are rejected with a ServiceUnavailable error and a
+// Retry-After, written like the other errors
+func TestOrdersOverload(t *testing.T) {
+	srv := httptest.NewServer(newServer(&weberr.Overload{MaxInFlight: 1}))
+	defer srv.Close()
+
+	// Hold the only slot with a request whose body is still being sent
+	body, bodyWriter := io.Pipe()
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := srv.Client().Post(srv.URL+"/orders", "application/json", body)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+	bodyWriter.Write([]byte(`{"id":"1",`))
+
+	// The first body chunk may still be on its way to the handler
+	var resp *http.Response
+	var err error
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		resp, err = request(t, srv, "GET", "/orders/1", "")
+		if weberr.GetType(err) != weberr.NotFound {
+			break
+		}
+	}
+	if weberr.GetType(err) != weberr.ServiceUnavailable || resp.Header.Get("Retry-After") == "" ||
+		weberr.GetUserMessage(err) == "" {
+		t.Errorf("got: %d %v %q, want a 503 with a Retry-After", resp.StatusCode, resp.Header, weberr.GetUserMessage(err))
+	}
+
+	bodyWriter.Write([]byte(`"name":"Coffee"}`))
+	bodyWriter.Close()
+	if resp := <-done; resp == nil || resp.StatusCode != http.StatusCreated {
+		t.Errorf("got: %v, want %d", resp, http.StatusCreated)
+	} else {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,49 @@
+// Package ginadapter translates weberr errors into Gin responses.
+package ginadapter
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/zgalor/weberr"
+)
+
+// ErrorHandler returns a middleware that writes the last error attached to
+// the context with c.Error, once the other handlers are done, unless a
+// response was already written.
+// If wr is nil, errors are written with weberr.WriteError.
+func ErrorHandler(wr *weberr.Writer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		writeError(wr, c, c.Errors.Last().Err)
+	}
+}
+
+// Wrap adapts a handler returning an error into a gin.HandlerFunc.
+// The error is attached to the context, written and the chain aborted.
+// If wr is nil, errors are written with weberr.WriteError.
+func Wrap(wr *weberr.Writer, h func(*gin.Context) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := h(c)
+		if err == nil {
+			return
+		}
+
+		c.Error(err)
+		c.Abort()
+		writeError(wr, c, err)
+	}
+}
+
+// writeError writes err with wr, or with weberr.WriteError if wr is nil
+func writeError(wr *weberr.Writer, c *gin.Context, err error) {
+	if wr != nil {
+		wr.WriteError(c.Writer, c.Request, err)
+		return
+	}
+
+	weberr.WriteError(c.Writer, c.Request, err)
+}
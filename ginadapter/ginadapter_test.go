@@ -0,0 +1,53 @@
+package ginadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zgalor/weberr"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestGinAdapter(t *testing.T) {
+	r := gin.New()
+	r.Use(ErrorHandler(nil))
+	r.GET("/wrapped", Wrap(nil, func(c *gin.Context) error {
+		return weberr.NotFound.UserErrorf("cluster not found")
+	}))
+	r.GET("/attached", func(c *gin.Context) {
+		c.Error(weberr.Conflict.UserErrorf("already exists"))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.Error(weberr.Conflict.UserErrorf("already exists"))
+		c.String(http.StatusAccepted, "accepted")
+	})
+	r.GET("/ok", Wrap(&weberr.Writer{}, func(c *gin.Context) error {
+		c.String(http.StatusOK, "ok")
+		return nil
+	}))
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/wrapped", 404, "cluster not found"},
+		{"/attached", 409, "already exists"},
+		{"/written", 202, "accepted"},
+		{"/ok", 200, "ok"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%s: got: %d %q, want %d %q", tt.path, rec.Code, rec.Body, tt.status, tt.body)
+		}
+	}
+}
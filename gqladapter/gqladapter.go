@@ -0,0 +1,87 @@
+// Package gqladapter translates weberr errors into GraphQL errors, for
+// gqlgen servers sharing their business logic with REST handlers:
+//
+//	srv := handler.NewDefaultServer(schema)
+//	srv.SetErrorPresenter(gqladapter.ErrorPresenter(nil))
+//
+// The user message of the error is the message of the GraphQL error, and
+// its status, code and details are extensions, as rendered for REST.
+package gqladapter
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/zgalor/weberr"
+)
+
+// ToGraphQLError converts err into a GraphQL error, with the package-level
+// exposure policy.
+func ToGraphQLError(err error) *gqlerror.Error {
+	return toGraphQLError(err, weberr.GetExposure())
+}
+
+// toGraphQLError converts err, transformed by the hooks of weberr, into a
+// GraphQL error, rendering what exposure allows
+func toGraphQLError(err error, exposure weberr.Exposure) *gqlerror.Error {
+	err = weberr.Present(err)
+	body := weberr.NewBody(err, exposure)
+
+	extensions := map[string]interface{}{"status": body.Status}
+	if body.Code != "" {
+		extensions["code"] = body.Code
+	}
+	if body.Details != nil {
+		extensions["details"] = body.Details
+	}
+	if body.Error != "" {
+		extensions["error"] = body.Error
+	}
+	if body.Stack != "" {
+		extensions["stack"] = body.Stack
+	}
+	if body.ID != "" {
+		extensions["id"] = body.ID
+	}
+
+	return &gqlerror.Error{
+		Err:        err,
+		Message:    body.Message,
+		Extensions: extensions,
+	}
+}
+
+// ErrorPresenter returns a gqlgen error presenter converting the errors
+// returned by resolvers with the exposure policy of wr, keeping their path.
+// Errors of gqlgen itself, such as query validation errors, are presented
+// as by default.
+// If wr is nil, the package-level exposure policy is used.
+func ErrorPresenter(wr *weberr.Writer) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		exposure := weberr.GetExposure()
+		if wr != nil {
+			exposure = wr.Exposure
+		}
+
+		// gqlgen wraps the errors of resolvers with their path, which
+		// middlewares can wrap in turn
+		var gqlErr *gqlerror.Error
+		if stderrors.As(err, &gqlErr) {
+			if gqlErr.Err == nil {
+				return gqlErr
+			}
+
+			presented := toGraphQLError(gqlErr.Err, exposure)
+			presented.Path = gqlErr.Path
+			presented.Locations = gqlErr.Locations
+			return presented
+		}
+
+		presented := toGraphQLError(err, exposure)
+		presented.Path = graphql.GetPath(ctx)
+		return presented
+	}
+}
@@ -0,0 +1,74 @@
+package gqladapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/zgalor/weberr"
+)
+
+func TestToGraphQLError(t *testing.T) {
+	defer weberr.SetExposure(weberr.GetExposure())
+	weberr.SetExposure(weberr.ExposeDetails)
+
+	tests := []struct {
+		err        error
+		message    string
+		extensions map[string]interface{}
+	}{
+		{weberr.SetCode(weberr.NotFound.UserErrorf("Order not found"), "orders.NOT_FOUND"), "Order not found",
+			map[string]interface{}{"status": 404, "code": weberr.Code("orders.NOT_FOUND")}},
+		{weberr.AddDetails(weberr.BadRequest.UserErrorf("Invalid"), "foo"), "Invalid",
+			map[string]interface{}{"status": 400, "details": []interface{}{"foo"}}},
+		{io.EOF, "Internal Server Error", map[string]interface{}{"status": 500}},
+	}
+	for _, tt := range tests {
+		got := ToGraphQLError(tt.err)
+		if got.Message != tt.message || got.Err != tt.err || len(got.Extensions) != len(tt.extensions) {
+			t.Errorf("got: %q %v %v, want %q %v", got.Message, got.Err, got.Extensions, tt.message, tt.extensions)
+		}
+		for key, value := range tt.extensions {
+			if key == "details" {
+				continue
+			}
+			if got.Extensions[key] != value {
+				t.Errorf("got: %s %v, want %v", key, got.Extensions[key], value)
+			}
+		}
+	}
+}
+
+func TestErrorPresenter(t *testing.T) {
+	presenter := ErrorPresenter(&weberr.Writer{Exposure: weberr.ExposeUserMessageOnly})
+	err := weberr.AddDetails(weberr.Forbidden.UserErrorf("Not yours"), "foo")
+	path := ast.Path{ast.PathName("order"), ast.PathIndex(0)}
+
+	got := presenter(context.Background(), gqlerror.WrapPath(path, err))
+	if got.Message != "Not yours" || got.Path.String() != "order[0]" || got.Extensions["status"] != 403 ||
+		got.Extensions["details"] != nil {
+		t.Errorf("got: %q %q %v", got.Message, got.Path, got.Extensions)
+	}
+
+	got = presenter(context.Background(), fmt.Errorf("resolving: %w", gqlerror.WrapPath(path, err)))
+	if got.Message != "Not yours" || got.Path.String() != "order[0]" || got.Extensions["status"] != 403 {
+		t.Errorf("got: %q %q %v, want the path of the wrapped GraphQL error", got.Message, got.Path, got.Extensions)
+	}
+
+	got = presenter(context.Background(), err)
+	if got.Message != "Not yours" || got.Path != nil {
+		t.Errorf("got: %q %q", got.Message, got.Path)
+	}
+
+	validation := gqlerror.Errorf("Cannot query field %q", "foo")
+	if got := presenter(context.Background(), validation); got != validation {
+		t.Errorf("got: %v, want %v unchanged", got, validation)
+	}
+	if got := presenter(context.Background(), fmt.Errorf("validating: %w", validation)); got != validation {
+		t.Errorf("got: %v, want %v unwrapped", got, validation)
+	}
+}
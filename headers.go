@@ -0,0 +1,56 @@
+package weberr
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// headerer identifies an error with HTTP response headers
+type headerer interface {
+	Headers() http.Header
+}
+
+// Headers returns the HTTP headers of the error response
+func (c *Error) Headers() http.Header { return c.headers }
+
+// GetHeaders returns the headers the Writer adds to the response of an
+// error, for all errors. If error is not `headerer` returns nil.
+// The returned headers must not be modified.
+func GetHeaders(err error) http.Header {
+	if headerErr, ok := err.(headerer); ok {
+		return headerErr.Headers()
+	}
+
+	return nil
+}
+
+// WithHeader adds a header to the response of an error, preserved by
+// wrapping, e.g. the challenge of an Unauthorized error:
+//
+//	err = weberr.WithHeader(err, "WWW-Authenticate", `Bearer realm="api"`)
+//
+// Values are added to the ones the error already has for the header.
+// Content-Type and the headers the Writer computes (e.g. Retry-After)
+// take precedence over the headers of the error.
+func WithHeader(err error, key, value string) error {
+	if err == nil {
+		return nil
+	}
+
+	// Copy the headers, as they are shared with the wrapped error
+	headers := GetHeaders(err).Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Add(key, value)
+
+	c := inherit(err)
+	c.headers = headers
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
@@ -0,0 +1,66 @@
+package weberr
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Headers logic tested:
+// Default: no headers
+// Headers are preserved by wrapping
+// Adding a header doesn't change the wrapped error
+// Headers are written, Content-Type and Retry-After taking precedence
+func TestGetHeaders(t *testing.T) {
+	challenge := `Bearer realm="api"`
+	withHeader := WithHeader(io.EOF, "WWW-Authenticate", challenge)
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithHeader(nil, "WWW-Authenticate", challenge), ""},
+		{withHeader, challenge},
+		{Wrapf(withHeader, "msg"), challenge},
+		{UserWrapf(withHeader, "msg"), challenge},
+		{AddDetails(withHeader, "foo"), challenge},
+		{Unauthorized.Set(withHeader), challenge},
+		{SetUserMessage(withHeader, "msg"), challenge},
+		{SetCode(withHeader, "A"), challenge},
+		{WithSeverity(withHeader, SeverityInfo), challenge},
+		{WithRetryAfter(withHeader, time.Second), challenge},
+		{New("msg").Wrap(withHeader).Err(), challenge},
+	}
+	for _, tt := range tests {
+		got := GetHeaders(tt.err).Get("WWW-Authenticate")
+		if got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+
+	twice := WithHeader(withHeader, "WWW-Authenticate", "Basic")
+	if got := GetHeaders(twice).Values("WWW-Authenticate"); len(got) != 2 {
+		t.Errorf("got: %q, want both challenges", got)
+	}
+	if got := GetHeaders(withHeader).Values("WWW-Authenticate"); len(got) != 1 {
+		t.Errorf("got: %q, want the wrapped error unchanged", got)
+	}
+
+	err := WithHeader(WithHeader(WithRetryAfter(Unauthorized.UserErrorf("Log in"), time.Second), "Retry-After", "60"), "Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	WriteError(rec, nil, WithHeader(err, "WWW-Authenticate", challenge))
+
+	expected := map[string]string{
+		"WWW-Authenticate": challenge,
+		"Content-Type":     "application/json; charset=utf-8",
+		"Retry-After":      "1",
+	}
+	for key, value := range expected {
+		if got := rec.Header().Values(key); len(got) != 1 || got[0] != value {
+			t.Errorf("got: %s %q, want %q", key, got, value)
+		}
+	}
+}
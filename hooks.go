@@ -0,0 +1,54 @@
+package weberr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hook transforms an error before it is rendered, e.g. to add details,
+// translate the errors of a library into typed errors, or redact messages.
+type Hook func(err error) error
+
+var (
+	// hooks holds the []Hook applied by Present, in registration order
+	hooks atomic.Value
+	// hooksMu serializes updates of hooks
+	hooksMu sync.Mutex
+)
+
+// Use adds hooks applied to every error rendered by the package (WriteError,
+// ToJSON) and the adapters, in the order they are added:
+//
+//	weberr.Use(func(err error) error {
+//		if errors.Is(err, context.DeadlineExceeded) {
+//			return weberr.GatewayTimeout.UserWrapf(err, "The request timed out")
+//		}
+//		return err
+//	})
+//
+// A hook returning nil leaves the error unchanged.
+// It should be called during initialization, before errors are rendered.
+func Use(h ...Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	current, _ := hooks.Load().([]Hook)
+	hooks.Store(append(append([]Hook(nil), current...), h...))
+}
+
+// Present returns err transformed by the hooks added with Use, as it is
+// rendered. Nil errors are not transformed.
+func Present(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	current, _ := hooks.Load().([]Hook)
+	for _, hook := range current {
+		if presented := hook(err); presented != nil {
+			err = presented
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,57 @@
+package weberr
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// resetHooks removes all the hooks, for tests
+func resetHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	hooks.Store([]Hook(nil))
+}
+
+// Hooks logic tested:
+// Hooks apply in order, when errors are rendered
+// Hooks returning nil leave the error unchanged
+// Nil errors are not transformed
+func TestUse(t *testing.T) {
+	defer resetHooks()
+
+	Use(func(err error) error {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return GatewayTimeout.UserWrapf(err, "The request timed out")
+		}
+		return err
+	}, func(err error) error {
+		return nil
+	})
+	Use(func(err error) error {
+		return AddDetails(err, "request-id")
+	})
+
+	got := Present(context.DeadlineExceeded)
+	if GetType(got) != GatewayTimeout || GetUserMessage(got) != "The request timed out" ||
+		!compare(GetDetails(got), []interface{}{"request-id"}) {
+		t.Errorf("got: %v %q %v", GetType(got), GetUserMessage(got), GetDetails(got))
+	}
+	if got := Present(nil); got != nil {
+		t.Errorf("got: %v, want nil", got)
+	}
+
+	rec := httptest.NewRecorder()
+	wr := Writer{Exposure: ExposeDetails}
+	wr.WriteError(rec, nil, context.DeadlineExceeded)
+	if rec.Code != 504 {
+		t.Errorf("got: %d, want %d", rec.Code, 504)
+	}
+
+	data, err := ToJSON(context.DeadlineExceeded)
+	if err != nil || GetType(FromJSON(data)) != GatewayTimeout {
+		t.Errorf("got: %s %v", data, err)
+	}
+}
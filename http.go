@@ -0,0 +1,178 @@
+package weberr
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// Exposure controls how much of an error is rendered to API clients.
+type Exposure uint
+
+const (
+	// ExposeUserMessageOnly renders only the status code and the user message.
+	// This is the default, and the only safe choice for production.
+	ExposeUserMessageOnly Exposure = iota
+	// ExposeDetails renders the error details in addition to the user message.
+	ExposeDetails
+	// ExposeAll renders the full internal error chain and stack trace.
+	// Meant for development only.
+	ExposeAll
+)
+
+// exposure is the package-level policy used by WriteError and ToJSON,
+// ExposeUserMessageOnly by default.
+var exposure atomic.Uint32
+
+// SetExposure sets the package-level exposure policy.
+// It should be called during initialization, before errors are rendered.
+func SetExposure(e Exposure) {
+	exposure.Store(uint32(e))
+}
+
+// GetExposure returns the package-level exposure policy.
+func GetExposure() Exposure {
+	return Exposure(exposure.Load())
+}
+
+// HTTPStatus returns the HTTP status code matching the error type.
+// NoType is mapped to 500 Internal Server Error.
+func (errorType ErrorType) HTTPStatus() int {
+	if errorType == NoType {
+		return http.StatusInternalServerError
+	}
+
+	return int(errorType)
+}
+
+// Body is the rendered representation of an error.
+type Body struct {
+	Status  int           `json:"status" xml:"status"`
+	Code    Code          `json:"code,omitempty" xml:"code,omitempty"`
+	Message string        `json:"message" xml:"message"`
+	Details []interface{} `json:"details,omitempty" xml:"details>detail,omitempty"`
+	Error   string        `json:"error,omitempty" xml:"error,omitempty"`
+	Stack   string        `json:"stack,omitempty" xml:"stack,omitempty"`
+	ID      string        `json:"id,omitempty" xml:"id,omitempty"`
+}
+
+// NewBody builds the rendered representation of err, limited by exposure.
+// If err has no user message, the status text is used instead, so internal
+// messages never leak unless exposure is ExposeAll.
+func NewBody(err error, exposure Exposure) *Body {
+	status := GetType(err).HTTPStatus()
+
+	body := &Body{
+		Status:  status,
+		Code:    GetCode(err),
+		Message: GetUserMessage(err),
+	}
+	if body.Message == "" {
+		body.Message = http.StatusText(status)
+	}
+
+	if exposure >= ExposeDetails {
+		body.Details = GetDetails(err)
+	}
+	if errorIDs.Load() {
+		body.ID = GetErrorID(err)
+	}
+
+	if exposure >= ExposeAll && err != nil {
+		body.Error = err.Error()
+		body.Stack = GetStackTrace(err)
+	}
+
+	return body
+}
+
+// ToJSON marshals err, transformed by the hooks, using the package-level
+// exposure policy.
+func ToJSON(err error) ([]byte, error) {
+	return json.Marshal(NewBody(Present(err), GetExposure()))
+}
+
+// Writer renders errors as HTTP responses.
+// The zero value is ready to use and exposes the user message only.
+type Writer struct {
+	// Exposure controls how much of the error is rendered.
+	Exposure Exposure
+	// Localizer resolves user messages in the request's Accept-Language.
+	// If nil, DefaultCatalog is used.
+	Localizer Localizer
+	// Unbuffered makes error responses bypass compression and buffering:
+	// the body is sent with a Content-Length and flushed right away.
+	// Compression middlewares deciding from the Content-Length, such as
+	// NYTimes/gziphandler, klauspost/compress/gzhttp and gin-contrib/gzip,
+	// leave responses shorter than their minimum size alone, while the chi
+	// and Echo ones compress them anyway. Proxies and CDNs leave the response
+	// alone because of Cache-Control: no-transform.
+	Unbuffered bool
+}
+
+// WriteError writes err to w as a response with the status matching its type,
+// in the media type preferred by the Accept header of r among the registered
+// encoders (see RegisterEncoder), JSON by default.
+// Errors are first transformed by the hooks (see Use). Errors with a retry
+// delay get a Retry-After header, and the headers of the error are added to
+// the response.
+func (wr *Writer) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	err = Present(err)
+
+	body := NewBody(err, wr.Exposure)
+	key, _ := getMessageKey(err)
+	if r != nil && key != "" {
+		body.Message = LocalizeUserMessage(err, wr.localizer(), r.Header.Get("Accept-Language"))
+	}
+
+	enc := negotiate(r)
+	data, encodeErr := enc.encode(body)
+	if encodeErr != nil {
+		// Details can hold anything, drop them rather than the whole body
+		body.Details = nil
+		data, _ = enc.encode(body)
+	}
+
+	for key, values := range GetHeaders(err) {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.Header().Set("Content-Type", enc.contentType)
+	w.Header().Add("Vary", "Accept")
+	if key != "" {
+		// The message is in the language of the request, caches must know
+		w.Header().Add("Vary", "Accept-Language")
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if d := GetRetryAfter(err); d > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	if wr.Unbuffered {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Cache-Control", "no-transform")
+	}
+	w.WriteHeader(body.Status)
+	w.Write(data)
+
+	if wr.Unbuffered {
+		http.NewResponseController(w).Flush()
+	}
+}
+
+// localizer returns the Localizer of the writer
+func (wr *Writer) localizer() Localizer {
+	if wr.Localizer != nil {
+		return wr.Localizer
+	}
+
+	return DefaultCatalog
+}
+
+// WriteError writes err to w using the package-level exposure policy.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	wr := Writer{Exposure: GetExposure()}
+	wr.WriteError(w, r, err)
+}
@@ -0,0 +1,179 @@
+package weberr
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		errorType ErrorType
+		expected  int
+	}{
+		{NoType, 500},
+		{BadRequest, 400},
+		{Forbidden, 403},
+		{NotFound, 404},
+		{RequestTimeout, 408},
+		{Gone, 410},
+		{PreconditionFailed, 412},
+		{UnprocessableEntity, 422},
+		{TooManyRequests, 429},
+		{InternalServerError, 500},
+		{Internal, 500},
+		{NotImplemented, 501},
+		{ServiceUnavailable, 503},
+		{GatewayTimeout, 504},
+		{Timeout, 504},
+	}
+	for _, tt := range tests {
+		got := tt.errorType.HTTPStatus()
+		if got != tt.expected {
+			t.Errorf("got: %d, want %d", got, tt.expected)
+		}
+
+		rec := httptest.NewRecorder()
+		WriteError(rec, nil, tt.errorType.Errorf("msg"))
+		if rec.Code != tt.expected {
+			t.Errorf("%d: got: %d response, want %d", tt.expected, rec.Code, tt.expected)
+		}
+		if tt.errorType != NoType && GetType(tt.errorType.Wrapf(io.EOF, "msg")) != tt.errorType {
+			t.Errorf("%d: got: type %v, want %v", tt.expected, GetType(tt.errorType.Wrapf(io.EOF, "msg")), tt.errorType)
+		}
+	}
+}
+
+// Exposure logic tested:
+// ExposeUserMessageOnly never renders the internal message or details
+// A missing user message falls back to the status text
+// ExposeAll renders the internal chain and stack
+func TestNewBody(t *testing.T) {
+	foo := "foo"
+	err := AddDetails(NotFound.UserWrapf(Wrapf(io.EOF, "internal"), "Not here"), foo)
+
+	tests := []struct {
+		err      error
+		exposure Exposure
+		expected Body
+	}{
+		{err, ExposeUserMessageOnly, Body{Status: 404, Message: "Not here"}},
+		{err, ExposeDetails, Body{Status: 404, Message: "Not here", Details: []interface{}{foo}}},
+		{io.EOF, ExposeUserMessageOnly, Body{Status: 500, Message: "Internal Server Error"}},
+		{BadRequest.Errorf("secret"), ExposeDetails, Body{Status: 400, Message: "Bad Request"}},
+		{nil, ExposeAll, Body{Status: 500, Message: "Internal Server Error"}},
+	}
+	for _, tt := range tests {
+		got := NewBody(tt.err, tt.exposure)
+		if got.Status != tt.expected.Status || got.Message != tt.expected.Message ||
+			!compare(got.Details, tt.expected.Details) || got.Error != "" || got.Stack != "" {
+			t.Errorf("got: %+v, want %+v", got, tt.expected)
+		}
+	}
+
+	got := NewBody(err, ExposeAll)
+	if got.Error != "internal: EOF" {
+		t.Errorf("got: %q, want %q", got.Error, "internal: EOF")
+	}
+	if got.Stack == "" {
+		t.Errorf("expected a stack trace")
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	defer SetExposure(GetExposure())
+
+	err := Conflict.UserWrapf(Errorf("duplicate key"), "Already exists")
+
+	for _, exposure := range []Exposure{ExposeUserMessageOnly, ExposeAll} {
+		SetExposure(exposure)
+
+		rec := httptest.NewRecorder()
+		WriteError(rec, httptest.NewRequest("GET", "/", nil), err)
+
+		if rec.Code != 409 {
+			t.Errorf("got: %d, want %d", rec.Code, 409)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("got: %q, want %q", ct, "application/json; charset=utf-8")
+		}
+
+		var body Body
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Message != "Already exists" {
+			t.Errorf("got: %q, want %q", body.Message, "Already exists")
+		}
+
+		leaked := body.Error != ""
+		if leaked != (exposure == ExposeAll) {
+			t.Errorf("exposure %d: got internal message %q", exposure, body.Error)
+		}
+	}
+}
+
+func TestWriteErrorUnbuffered(t *testing.T) {
+	for _, unbuffered := range []bool{false, true} {
+		wr := Writer{Unbuffered: unbuffered}
+
+		rec := httptest.NewRecorder()
+		wr.WriteError(rec, httptest.NewRequest("GET", "/", nil), NotFound.UserErrorf("Not here"))
+
+		if rec.Flushed != unbuffered {
+			t.Errorf("unbuffered %v: got flushed %v", unbuffered, rec.Flushed)
+		}
+
+		length := rec.Header().Get("Content-Length")
+		if unbuffered && length != strconv.Itoa(rec.Body.Len()) {
+			t.Errorf("got: Content-Length %q, want %d", length, rec.Body.Len())
+		}
+		if !unbuffered && (length != "" || rec.Header().Get("Cache-Control") != "") {
+			t.Errorf("got: unexpected headers %v", rec.Header())
+		}
+		if unbuffered && rec.Header().Get("Cache-Control") != "no-transform" {
+			t.Errorf("got: Cache-Control %q, want no-transform", rec.Header().Get("Cache-Control"))
+		}
+		// RFC 9110 reserves identity, it must not be sent
+		if _, ok := rec.Header()["Content-Encoding"]; ok {
+			t.Errorf("got: unexpected Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+		}
+	}
+}
+
+func TestWriteErrorUnmarshalableDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	wr := Writer{Exposure: ExposeDetails}
+	wr.WriteError(rec, nil, AddDetails(NotFound.UserErrorf("Not here"), func() {}))
+
+	var body Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Message != "Not here" || body.Details != nil {
+		t.Errorf("got: %+v", body)
+	}
+}
+
+// The package-level policies can be set while errors are rendered, see -race
+func TestSetExposureConcurrently(t *testing.T) {
+	defer SetExposure(ExposeUserMessageOnly)
+	defer OnMissingTranslation(nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetExposure(Exposure(i % 3))
+			OnMissingTranslation(func(key, locale string) {})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", "xx")
+		WriteError(httptest.NewRecorder(), r, UserErrorfKey("missing.KEY"))
+	}
+	<-done
+}
@@ -0,0 +1,90 @@
+// Package k8s translates Kubernetes API errors, as returned by client-go,
+// into weberr errors, for the API servers and operators that proxy them to
+// their users.
+package k8s
+
+import (
+	stderrors "errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zgalor/weberr"
+)
+
+// reason is the weberr type and user message of a Kubernetes status reason
+type reason struct {
+	errorType   weberr.ErrorType
+	userMessage string
+}
+
+var reasons = map[metav1.StatusReason]reason{
+	metav1.StatusReasonUnauthorized:          {weberr.Unauthorized, "Authentication is required"},
+	metav1.StatusReasonForbidden:             {weberr.Forbidden, "You are not allowed to perform this operation"},
+	metav1.StatusReasonNotFound:              {weberr.NotFound, "The resource was not found"},
+	metav1.StatusReasonAlreadyExists:         {weberr.Conflict, "The resource already exists"},
+	metav1.StatusReasonConflict:              {weberr.Conflict, "The resource was modified concurrently, please retry"},
+	metav1.StatusReasonGone:                  {weberr.Gone, "The resource is gone"},
+	metav1.StatusReasonExpired:               {weberr.Gone, "The resource version is too old, please reload the resource"},
+	metav1.StatusReasonInvalid:               {weberr.UnprocessableEntity, "The resource is invalid"},
+	metav1.StatusReasonBadRequest:            {weberr.BadRequest, "The request is invalid"},
+	metav1.StatusReasonMethodNotAllowed:      {weberr.MethodNotAllowed, "The operation is not supported"},
+	metav1.StatusReasonNotAcceptable:         {weberr.NotAcceptable, "The requested format is not supported"},
+	metav1.StatusReasonUnsupportedMediaType:  {weberr.UnsupportedMediaType, "The request format is not supported"},
+	metav1.StatusReasonRequestEntityTooLarge: {weberr.RequestEntityTooLarge, "The request is too large"},
+	metav1.StatusReasonTooManyRequests:       {weberr.TooManyRequests, "Too many requests, please retry later"},
+	metav1.StatusReasonServerTimeout:         {weberr.ServiceUnavailable, "The operation could not be completed in time, please retry"},
+	metav1.StatusReasonTimeout:               {weberr.GatewayTimeout, "The operation timed out"},
+	metav1.StatusReasonServiceUnavailable:    {weberr.ServiceUnavailable, "The service is unavailable, please retry later"},
+	metav1.StatusReasonInternalError:         {weberr.InternalServerError, ""},
+}
+
+// FromK8sError converts a Kubernetes API error into a weberr error wrapping
+// it, with the type and a user message matching its reason. Errors with an
+// unknown reason get the type of their status code.
+// The kind and name of the resource, and the causes of an invalid resource,
+// are added as details, and the delay suggested by the API server as retry
+// delay.
+// Other errors are returned unchanged.
+func FromK8sError(err error) error {
+	var apiStatus apierrors.APIStatus
+	if !stderrors.As(err, &apiStatus) {
+		return err
+	}
+	status := apiStatus.Status()
+	delay, hasDelay := apierrors.SuggestsClientDelay(err)
+
+	r, ok := reasons[status.Reason]
+	if !ok {
+		r.errorType = weberr.InternalServerError
+		if status.Code >= 400 && status.Code <= 599 {
+			r.errorType = weberr.ErrorType(status.Code)
+		}
+	}
+
+	if r.userMessage != "" {
+		err = r.errorType.UserWrapf(err, "%s", r.userMessage)
+	} else {
+		err = r.errorType.Set(err)
+	}
+
+	if d := status.Details; d != nil {
+		if d.Kind != "" || d.Name != "" {
+			err = weberr.AddDetails(err, map[string]string{"group": d.Group, "kind": d.Kind, "name": d.Name})
+		}
+		for _, cause := range d.Causes {
+			err = weberr.AddDetails(err, map[string]string{
+				"field":   cause.Field,
+				"reason":  string(cause.Type),
+				"message": cause.Message,
+			})
+		}
+	}
+
+	if hasDelay {
+		err = weberr.WithRetryAfter(err, time.Duration(delay)*time.Second)
+	}
+
+	return err
+}
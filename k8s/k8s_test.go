@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/zgalor/weberr"
+)
+
+func TestFromK8sError(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	notFound := apierrors.NewNotFound(pods, "web-0")
+
+	tests := []struct {
+		err      error
+		errType  weberr.ErrorType
+		usrMsg   string
+		details  int
+		retry    time.Duration
+		expected string
+	}{
+		{nil, weberr.NoType, "", 0, 0, ""},
+		{io.EOF, weberr.NoType, "", 0, 0, "EOF"},
+		{notFound, weberr.NotFound, "The resource was not found", 1, 0, notFound.Error()},
+		{apierrors.NewConflict(pods, "web-0", io.EOF), weberr.Conflict, "The resource was modified concurrently, please retry", 1, 0, ""},
+		{apierrors.NewAlreadyExists(pods, "web-0"), weberr.Conflict, "The resource already exists", 1, 0, ""},
+		{apierrors.NewForbidden(pods, "web-0", io.EOF), weberr.Forbidden, "You are not allowed to perform this operation", 1, 0, ""},
+		{apierrors.NewUnauthorized("token expired"), weberr.Unauthorized, "Authentication is required", 0, 0, ""},
+		{apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "web-0", field.ErrorList{
+			field.Required(field.NewPath("spec", "containers"), ""),
+			field.Invalid(field.NewPath("metadata", "name"), "Web", "must be lowercase"),
+		}), weberr.UnprocessableEntity, "The resource is invalid", 3, 0, ""},
+		{apierrors.NewTooManyRequests("slow down", 3), weberr.TooManyRequests, "Too many requests, please retry later", 0, 3 * time.Second, ""},
+		{apierrors.NewServerTimeout(pods, "list", 2), weberr.ServiceUnavailable, "The operation could not be completed in time, please retry", 1, 2 * time.Second, ""},
+		{apierrors.NewInternalError(io.EOF), weberr.InternalServerError, "", 1, 0, ""},
+		{apierrors.NewGenericServerResponse(418, "get", pods, "web-0", "", 0, false), weberr.Teapot, "", 1, 0, ""},
+	}
+	for _, tt := range tests {
+		got := FromK8sError(tt.err)
+		if got == nil {
+			if tt.err != nil {
+				t.Errorf("%v: got nil", tt.err)
+			}
+			continue
+		}
+		if weberr.GetType(got) != tt.errType || weberr.GetUserMessage(got) != tt.usrMsg ||
+			len(weberr.GetDetails(got)) != tt.details || weberr.GetRetryAfter(got) != tt.retry {
+			t.Errorf("%v: got: %v %q %v %v, want %v %q %d %v", tt.err, weberr.GetType(got), weberr.GetUserMessage(got),
+				weberr.GetDetails(got), weberr.GetRetryAfter(got), tt.errType, tt.usrMsg, tt.details, tt.retry)
+		}
+		if tt.expected != "" && got.Error() != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+
+	details := weberr.GetDetails(FromK8sError(notFound))
+	if d, ok := details[0].(map[string]string); !ok || d["kind"] != "pods" || d["name"] != "web-0" {
+		t.Errorf("got: %v", details)
+	}
+}
@@ -0,0 +1,229 @@
+package weberr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// Localizer resolves a message key to a format string in the given locale.
+// Catalog implements Localizer; other message catalogs (e.g. go-i18n bundles)
+// can be plugged in through a small adapter.
+type Localizer interface {
+	Localize(key string, locale string) (string, bool)
+}
+
+// Localize returns the message format of the code named key, in locale.
+func (c *Catalog) Localize(key string, locale string) (string, bool) {
+	entry, ok := c.Entry(Code(key))
+	if !ok {
+		return "", false
+	}
+
+	if strings.EqualFold(locale, c.defaultLocale) {
+		return entry.Message, true
+	}
+
+	for l, msg := range entry.Translations {
+		if strings.EqualFold(l, locale) {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// localizable identifies an error with a user message that can be localized
+type localizable interface {
+	MessageKey() (string, []interface{})
+}
+
+// MessageKey returns the user message key and its arguments
+func (c *Error) MessageKey() (string, []interface{}) { return c.messageKey, c.messageArgs }
+
+// getMessageKey returns the user message key and arguments of err, if any
+func getMessageKey(err error) (string, []interface{}) {
+	if l, ok := err.(localizable); ok {
+		return l.MessageKey()
+	}
+
+	return "", nil
+}
+
+// UserErrorfKey creates a new error with a user message resolved from the
+// default catalog entry whose code is key.
+// The message is rendered in the request's locale by the Writer, while
+// GetUserMessage returns it in the catalog default locale.
+// The error gets the code key, and the type of the entry if called on NoType.
+func (errorType ErrorType) UserErrorfKey(key string, args ...interface{}) error {
+	message, newType := key, errorType
+	if entry, ok := DefaultCatalog.Entry(Code(key)); ok {
+		message = fmt.Sprintf(entry.Message, args...)
+		if newType == NoType {
+			newType = entry.Type
+		}
+	}
+
+	err := plainErrorf("%s", message)
+	if captureStack(newType) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:       err,
+		errorType:   newType,
+		userMessage: message,
+		code:        Code(key),
+		messageKey:  key,
+		messageArgs: args,
+	}
+}
+
+// UserErrorfKey returns an error with a user message resolved from the default catalog.
+func UserErrorfKey(key string, args ...interface{}) error {
+	return NoType.UserErrorfKey(key, args...)
+}
+
+// LocalizeUserMessage returns the user message of err in the first locale
+// of acceptLanguage (an Accept-Language header value) that l can resolve.
+// Each locale falls back to its parents (fr-CA, then fr) before moving to
+// the next one, and the default locale message (GetUserMessage) is used last.
+// Locales that could not be resolved are reported to the missing
+// translation hook.
+func LocalizeUserMessage(err error, l Localizer, acceptLanguage string) string {
+	key, args := getMessageKey(err)
+	if key == "" {
+		return GetUserMessage(err)
+	}
+
+	for _, locale := range parseAcceptLanguage(acceptLanguage) {
+		if format, ok := localize(l, key, locale); ok {
+			return fmt.Sprintf(format, args...)
+		}
+		if hook := missingTranslationHook.Load(); hook != nil {
+			(*hook)(key, locale)
+		}
+	}
+
+	return GetUserMessage(err)
+}
+
+// localize resolves key in locale or in one of its parent locales.
+// Empty formats are considered missing.
+func localize(l Localizer, key string, locale string) (string, bool) {
+	for _, candidate := range localeChain(locale) {
+		if format, ok := l.Localize(key, candidate); ok && format != "" {
+			return format, true
+		}
+	}
+
+	return "", false
+}
+
+// localeChain returns locale followed by its parents,
+// e.g. zh-Hant-TW, zh-Hant, zh.
+func localeChain(locale string) []string {
+	chain := []string{locale}
+	for i := strings.LastIndexAny(locale, "-_"); i > 0; i = strings.LastIndexAny(locale, "-_") {
+		locale = locale[:i]
+		chain = append(chain, locale)
+	}
+
+	return chain
+}
+
+// missingTranslationHook is called for locales missing a translation
+var missingTranslationHook atomic.Pointer[func(key, locale string)]
+
+// OnMissingTranslation sets a hook called with the key and locale of every
+// requested translation that is missing, e.g. to count them and backfill the
+// catalog. Requests for unsupported locales are reported too, so the hook may
+// need to filter them.
+// It should be called during initialization, before errors are rendered.
+func OnMissingTranslation(hook func(key, locale string)) {
+	if hook == nil {
+		missingTranslationHook.Store(nil)
+		return
+	}
+	missingTranslationHook.Store(&hook)
+}
+
+// MissingTranslation identifies a catalog entry without a translation.
+type MissingTranslation struct {
+	Code   Code
+	Locale string
+}
+
+// MissingTranslations returns, for every entry, the supported locales that
+// can't be resolved either directly or through a parent locale.
+func (c *Catalog) MissingTranslations() []MissingTranslation {
+	var missing []MissingTranslation
+	for _, entry := range c.Entries() {
+		for _, locale := range c.locales {
+			if _, ok := localize(c, string(entry.Code), locale); !ok {
+				missing = append(missing, MissingTranslation{Code: entry.Code, Locale: locale})
+			}
+		}
+	}
+
+	return missing
+}
+
+// parseAcceptLanguage returns the locales of an Accept-Language header,
+// by decreasing preference. Wildcards and refused locales (q=0) are omitted.
+func parseAcceptLanguage(header string) []string {
+	locales := []string{}
+	for _, locale := range parseAccept(header) {
+		if locale != "*" {
+			locales = append(locales, locale)
+		}
+	}
+
+	return locales
+}
+
+// parseAccept returns the values of an Accept or Accept-Language header,
+// without their parameters, by decreasing preference.
+// Refused values (q=0) are omitted.
+func parseAccept(header string) []string {
+	type weighted struct {
+		value string
+		q     float64
+	}
+
+	var values []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		value := strings.TrimSpace(fields[0])
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				v, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					v = 0
+				}
+				q = v
+			}
+		}
+		if q > 0 {
+			values = append(values, weighted{value, q})
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].q > values[j].q })
+
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.value
+	}
+
+	return result
+}
@@ -0,0 +1,165 @@
+package weberr
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{"", []string{}},
+		{"fr", []string{"fr"}},
+		{"fr-CA, fr;q=0.9, en;q=0.8, *;q=0.5", []string{"fr-CA", "fr", "en"}},
+		{"en;q=0.5, de", []string{"de", "en"}},
+		{"en;q=0, de;q=bad, fr", []string{"fr"}},
+	}
+	for _, tt := range tests {
+		got := parseAcceptLanguage(tt.header)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%q: got: %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+// Localization logic tested:
+// GetUserMessage returns the message in the default locale
+// The first locale of Accept-Language the catalog can resolve is used
+// Keys missing from the catalog are used as messages
+// UserWrapf and SetUserMessage replace the localizable message
+func TestLocalizeUserMessage(t *testing.T) {
+	defer func(c *Catalog) { DefaultCatalog = c }(DefaultCatalog)
+
+	DefaultCatalog = NewCatalog("en", "fr")
+	Register(CatalogEntry{
+		Code:         "orders.NOT_FOUND",
+		Type:         NotFound,
+		Message:      "order %d not found",
+		Translations: map[string]string{"fr": "commande %d introuvable"},
+	})
+
+	err := UserErrorfKey("orders.NOT_FOUND", 42)
+	if got := GetUserMessage(err); got != "order 42 not found" {
+		t.Errorf("got: %q, want %q", got, "order 42 not found")
+	}
+	if GetType(err) != NotFound || GetCode(err) != "orders.NOT_FOUND" {
+		t.Errorf("got: %v %q, want %v %q", GetType(err), GetCode(err), NotFound, "orders.NOT_FOUND")
+	}
+	if got := GetType(Gone.UserErrorfKey("orders.NOT_FOUND", 42)); got != Gone {
+		t.Errorf("got: %v, want %v", got, Gone)
+	}
+
+	tests := []struct {
+		err            error
+		acceptLanguage string
+		expected       string
+	}{
+		{err, "", "order 42 not found"},
+		{err, "fr", "commande 42 introuvable"},
+		{err, "de, fr;q=0.5", "commande 42 introuvable"},
+		{err, "de", "order 42 not found"},
+		{Wrapf(AddDetails(err, "foo"), "internal"), "fr", "commande 42 introuvable"},
+		{NotFound.Set(err), "fr", "commande 42 introuvable"},
+		{UserWrapf(err, "outer"), "fr", "outer: order 42 not found"},
+		{SetUserMessage(err, "replaced"), "fr", "replaced"},
+		{UserErrorfKey("missing.KEY"), "fr", "missing.KEY"},
+		{io.EOF, "fr", ""},
+	}
+	for _, tt := range tests {
+		got := LocalizeUserMessage(tt.err, DefaultCatalog, tt.acceptLanguage)
+		if got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "fr-CA, fr;q=0.8")
+	WriteError(rec, req, err)
+
+	var body Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Message != "commande 42 introuvable" || body.Status != 404 {
+		t.Errorf("got: %+v", body)
+	}
+	if vary := rec.Header().Values("Vary"); !reflect.DeepEqual(vary, []string{"Accept", "Accept-Language"}) {
+		t.Errorf("got: Vary %q, want Accept and Accept-Language", vary)
+	}
+
+	// Messages without key don't depend on the language
+	rec = httptest.NewRecorder()
+	WriteError(rec, req, NotFound.UserErrorf("Not here"))
+	if vary := rec.Header().Values("Vary"); !reflect.DeepEqual(vary, []string{"Accept"}) {
+		t.Errorf("got: Vary %q, want Accept", vary)
+	}
+}
+
+func TestLocaleChain(t *testing.T) {
+	tests := []struct {
+		locale   string
+		expected []string
+	}{
+		{"fr", []string{"fr"}},
+		{"fr-CA", []string{"fr-CA", "fr"}},
+		{"zh_Hant-TW", []string{"zh_Hant-TW", "zh_Hant", "zh"}},
+	}
+	for _, tt := range tests {
+		got := localeChain(tt.locale)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+// Fallback logic tested:
+// Regional locales fall back to their language
+// Empty translations are considered missing
+// Missing translations are reported to the hook, then the default message is used
+func TestLocalizeFallback(t *testing.T) {
+	defer OnMissingTranslation(nil)
+
+	c := NewCatalog("en", "fr", "fr-CA", "de")
+	c.Add(CatalogEntry{
+		Code:         "A",
+		Type:         BadRequest,
+		Message:      "default",
+		Translations: map[string]string{"fr": "français", "de": ""},
+	})
+	// Not using UserErrorfKey, as the entry isn't in the default catalog
+	err := &Error{error: Errorf("internal"), userMessage: "default", messageKey: "A"}
+
+	var missing []string
+	OnMissingTranslation(func(key, locale string) {
+		missing = append(missing, key+":"+locale)
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+		missing        []string
+	}{
+		{"fr-CA", "français", nil},
+		{"de, fr-CA;q=0.5", "français", []string{"A:de"}},
+		{"de, it", "default", []string{"A:de", "A:it"}},
+		{"", "default", nil},
+	}
+	for _, tt := range tests {
+		missing = nil
+		got := LocalizeUserMessage(err, c, tt.acceptLanguage)
+		if got != tt.expected || !reflect.DeepEqual(missing, tt.missing) {
+			t.Errorf("%q: got: %q %q, want %q %q", tt.acceptLanguage, got, missing, tt.expected, tt.missing)
+		}
+	}
+
+	expected := []MissingTranslation{{Code: "A", Locale: "de"}}
+	if got := c.MissingTranslations(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, want %v", got, expected)
+	}
+}
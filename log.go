@@ -0,0 +1,36 @@
+package weberr
+
+import "log/slog"
+
+// LogValue returns the attributes of the error as a slog group, so that
+// logging it with log/slog records them as fields rather than only the
+// message:
+//
+//	slog.Error("request failed", "err", err)
+//
+// The group has the message, type, ID (see GetErrorID), code, user message,
+// severity, details and stack trace (as GetStackFrames) of the error, the
+// empty ones omitted.
+// Secret details are logged as ***.
+func (c *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("message", c.Error()),
+		slog.Int("type", c.errorType.HTTPStatus()),
+		slog.String("id", GetErrorID(c)),
+	}
+	if c.code != "" {
+		attrs = append(attrs, slog.String("code", string(c.code)))
+	}
+	if c.userMessage != "" {
+		attrs = append(attrs, slog.String("user_message", c.userMessage))
+	}
+	attrs = append(attrs, slog.String("severity", GetSeverity(c).String()))
+	if len(c.details) > 0 {
+		attrs = append(attrs, slog.Any("details", c.details))
+	}
+	if frames := GetStackFrames(c); frames != nil {
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,48 @@
+package weberr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	err := WithSecretDetail(SetCode(NotFound.UserWrapf(Errorf("no rows"), "Order not found"), "orders.NOT_FOUND"), "token", "s3cr3t")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("request failed", "err", err)
+
+	var record struct {
+		Err struct {
+			Message     string                   `json:"message"`
+			Type        int                      `json:"type"`
+			ID          string                   `json:"id"`
+			Code        string                   `json:"code"`
+			UserMessage string                   `json:"user_message"`
+			Severity    string                   `json:"severity"`
+			Details     []map[string]string      `json:"details"`
+			Stack       []map[string]interface{} `json:"stack"`
+		} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("%s: %v", buf.Bytes(), err)
+	}
+
+	got := record.Err
+	if got.Message != "no rows" || got.Type != 404 || got.ID != GetErrorID(err) || got.Code != "orders.NOT_FOUND" || got.UserMessage != "Order not found" ||
+		got.Severity != "info" || len(got.Details) != 1 || got.Details[0]["token"] != "***" {
+		t.Errorf("got: %s", buf.Bytes())
+	}
+	if len(got.Stack) == 0 || got.Stack[0]["function"] != "testing.tRunner" {
+		t.Errorf("got: stack %v", got.Stack)
+	}
+
+	buf.Reset()
+	slog.New(slog.NewTextHandler(&buf, nil)).Error("request failed", "err", NoType.Wrapf(io.EOF, "reading"))
+	if !regexp.MustCompile(`err.message="reading: EOF" err.type=500 err.id=[0-9a-f]{12} err.severity=error`).Match(buf.Bytes()) {
+		t.Errorf("got: %s", buf.Bytes())
+	}
+}
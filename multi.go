@@ -0,0 +1,135 @@
+package weberr
+
+import (
+	stderrors "errors"
+	"strings"
+	"sync"
+)
+
+// multiError is the error wrapped by Join, holding the joined errors
+type multiError interface {
+	Unwrap() []error
+}
+
+// Join returns an error aggregating errs, or nil if they are all nil.
+// Its type is the type of the worst failure: the first server error (5xx),
+// untyped errors counting as InternalServerError, or else the first client
+// error (4xx). Its user message joins the distinct user messages of errs
+// with "; ", its details are all their details, and its retry delay is the
+// longest of theirs. GetErrors returns the joined errors.
+func Join(errs ...error) error {
+	var joined []error
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+		}
+	}
+	if len(joined) == 0 {
+		return nil
+	}
+
+	c := &Error{error: stderrors.Join(joined...)}
+
+	var userMessages []string
+	seen := map[string]bool{}
+	for _, err := range joined {
+		if worse(GetType(err), c.errorType) {
+			c.errorType = GetType(err)
+			if c.errorType == NoType {
+				c.errorType = InternalServerError
+			}
+		}
+		if msg := GetUserMessage(err); msg != "" && !seen[msg] {
+			seen[msg] = true
+			userMessages = append(userMessages, msg)
+		}
+		c.details = append(c.details, GetDetails(err)...)
+		if d := GetRetryAfter(err); d > c.retryAfter {
+			c.retryAfter = d
+		}
+	}
+	c.userMessage = strings.Join(userMessages, "; ")
+
+	return c
+}
+
+// worse reports whether errorType is a worse failure than current, the
+// worst type so far
+func worse(errorType, current ErrorType) bool {
+	if current == NoType {
+		return true
+	}
+
+	rank := func(t ErrorType) int {
+		switch status := t.HTTPStatus(); {
+		case status >= 500:
+			return 2
+		case status >= 400:
+			return 1
+		}
+		return 0
+	}
+
+	return rank(errorType) > rank(current)
+}
+
+// GetErrors returns the errors joined by Join, also when err wraps the
+// joined error, or nil if err doesn't join errors.
+func GetErrors(err error) []error {
+	for _, err := range Chain(err) {
+		if multi, ok := err.(multiError); ok {
+			return multi.Unwrap()
+		}
+	}
+
+	return nil
+}
+
+// Group runs functions in goroutines and collects all their errors, unlike
+// golang.org/x/sync/errgroup which only keeps the first one:
+//
+//	var g weberr.Group
+//	for _, backend := range backends {
+//		backend := backend
+//		g.Go(func() error { return backend.Fetch(ctx) })
+//	}
+//	if err := g.Wait(); err != nil {
+//		weberr.WriteError(w, r, err)
+//	}
+//
+// The zero value is ready to use.
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go calls f in a new goroutine.
+func (g *Group) Go(f func() error) {
+	g.mu.Lock()
+	i := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := f()
+
+		g.mu.Lock()
+		g.errs[i] = err
+		g.mu.Unlock()
+	}()
+}
+
+// Wait waits for all the functions to return, and returns their errors
+// joined by Join, in the order the functions were passed to Go.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return Join(g.errs...)
+}
@@ -0,0 +1,94 @@
+package weberr
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		errs     []error
+		expected ErrorType
+	}{
+		{[]error{NotFound.Errorf("a"), BadRequest.Errorf("b")}, NotFound},
+		{[]error{NotFound.Errorf("a"), ServiceUnavailable.Errorf("b")}, ServiceUnavailable},
+		{[]error{NotFound.Errorf("a"), io.EOF}, InternalServerError},
+		{[]error{io.EOF, ServiceUnavailable.Errorf("b")}, InternalServerError},
+		{[]error{nil, Unauthorized.Errorf("a")}, Unauthorized},
+	}
+	for _, tt := range tests {
+		if got := GetType(Join(tt.errs...)); got != tt.expected {
+			t.Errorf("%v: got: %v, want %v", tt.errs, got, tt.expected)
+		}
+	}
+
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("got: %v, want nil", err)
+	}
+}
+
+func TestJoinAttributes(t *testing.T) {
+	a := AddDetails(NotFound.UserErrorf("Order not found"), "order")
+	b := WithRetryAfter(AddDetails(NotFound.UserErrorf("Order not found"), "cart"), time.Second)
+	c := WithRetryAfter(BadRequest.UserErrorf("Bad cart"), time.Minute)
+	err := Join(a, b, c)
+
+	if got, expected := GetUserMessage(err), "Order not found; Bad cart"; got != expected {
+		t.Errorf("got: %q, want %q", got, expected)
+	}
+	if got := GetDetails(err); len(got) != 2 || got[0] != "order" || got[1] != "cart" {
+		t.Errorf("got: %v, want [order cart]", got)
+	}
+	if got := GetRetryAfter(err); got != time.Minute {
+		t.Errorf("got: %v, want %v", got, time.Minute)
+	}
+	if got, expected := err.Error(), "Order not found\nOrder not found\nBad cart"; got != expected {
+		t.Errorf("got: %q, want %q", got, expected)
+	}
+
+	errs := GetErrors(Wrapf(err, "fetching"))
+	if len(errs) != 3 || errs[0] != a || errs[1] != b || errs[2] != c {
+		t.Errorf("got: %v, want %v", errs, []error{a, b, c})
+	}
+	if got := GetErrors(a); got != nil {
+		t.Errorf("got: %v, want nil", got)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+
+	a := NotFound.Errorf("a")
+	b := ServiceUnavailable.Errorf("b")
+	for _, err := range []error{a, nil, b} {
+		err := err
+		g.Go(func() error {
+			calls.Add(1)
+			if err == a {
+				// Finishes last, but is reported first
+				time.Sleep(10 * time.Millisecond)
+			}
+			return err
+		})
+	}
+
+	err := g.Wait()
+	if calls.Load() != 3 {
+		t.Errorf("got: %d calls, want 3", calls.Load())
+	}
+	if got := GetType(err); got != ServiceUnavailable {
+		t.Errorf("got: %v, want %v", got, ServiceUnavailable)
+	}
+	if errs := GetErrors(err); len(errs) != 2 || errs[0] != a || errs[1] != b {
+		t.Errorf("got: %v, want %v", errs, []error{a, b})
+	}
+
+	var empty Group
+	empty.Go(func() error { return nil })
+	if err := empty.Wait(); err != nil {
+		t.Errorf("got: %v, want nil", err)
+	}
+}
@@ -0,0 +1,121 @@
+package weberr
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Overload is an HTTP middleware that sheds load: it limits the requests
+// served concurrently and the requests waiting for their turn, and rejects
+// the others with a ServiceUnavailable error. The Retry-After of the error
+// estimates when the service can take the request, from the number of
+// waiting requests and the recent latency of the handler:
+//
+//	handler = (&weberr.Overload{MaxInFlight: 100, MaxQueue: 200}).Handler(handler)
+type Overload struct {
+	// MaxInFlight is the number of requests served concurrently, at least 1
+	MaxInFlight int
+	// MaxQueue is the number of requests that wait for a request to
+	// complete when MaxInFlight requests are served. Others are rejected.
+	MaxQueue int
+	// QueueTimeout is how long a request waits before being rejected.
+	// Zero means waiting until the request is canceled.
+	QueueTimeout time.Duration
+	// MaxRetryAfter caps the computed Retry-After. Zero means a minute.
+	MaxRetryAfter time.Duration
+	// Writer writes the errors. Nil means using WriteError.
+	Writer *Writer
+}
+
+// overloadMessage is the user message of the errors of Overload
+const overloadMessage = "The service is overloaded, please retry later"
+
+// Handler returns next, limited by o.
+// Every call returns a handler with its own limits.
+// It panics if MaxInFlight isn't positive, as every request would be shed.
+func (o *Overload) Handler(next http.Handler) http.Handler {
+	if o.MaxInFlight < 1 {
+		panic(fmt.Sprintf("weberr: invalid Overload.MaxInFlight %d", o.MaxInFlight))
+	}
+
+	slots := make(chan struct{}, o.MaxInFlight)
+	var queued atomic.Int64
+	var latency atomic.Int64
+
+	writeError := WriteError
+	if o.Writer != nil {
+		writeError = o.Writer.WriteError
+	}
+
+	shed := func(w http.ResponseWriter, r *http.Request) {
+		retryAfter := o.retryAfter(time.Duration(latency.Load()), queued.Load())
+		writeError(w, r, WithRetryAfter(ServiceUnavailable.UserErrorfNoStack(overloadMessage), retryAfter))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if queued.Add(1) > int64(o.MaxQueue) {
+				queued.Add(-1)
+				shed(w, r)
+				return
+			}
+
+			var timeout <-chan time.Time
+			if o.QueueTimeout > 0 {
+				timer := time.NewTimer(o.QueueTimeout)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+
+			select {
+			case slots <- struct{}{}:
+				queued.Add(-1)
+			case <-timeout:
+				queued.Add(-1)
+				shed(w, r)
+				return
+			case <-r.Context().Done():
+				queued.Add(-1)
+				shed(w, r)
+				return
+			}
+		}
+		defer func() { <-slots }()
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+
+		// Moving average of the latency, favoring recent requests
+		previous := time.Duration(latency.Load())
+		latency.Store(int64(previous + (time.Since(start)-previous)/8))
+	})
+}
+
+// retryAfter estimates how long the requests already waiting take to be
+// served, given the latency of the handler
+func (o *Overload) retryAfter(latency time.Duration, queued int64) time.Duration {
+	max := o.MaxRetryAfter
+	if max <= 0 {
+		max = time.Minute
+	}
+
+	inFlight := int64(o.MaxInFlight)
+	if inFlight < 1 {
+		inFlight = 1
+	}
+
+	// Every slot serves its share of the queue, then the request
+	d := latency * time.Duration(queued/inFlight+1)
+	switch {
+	case d < time.Second:
+		return time.Second
+	case d > max:
+		return max
+	}
+
+	return d
+}
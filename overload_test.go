@@ -0,0 +1,98 @@
+package weberr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOverloadRetryAfter(t *testing.T) {
+	o := &Overload{MaxInFlight: 2, MaxRetryAfter: 10 * time.Second}
+
+	tests := []struct {
+		latency  time.Duration
+		queued   int64
+		expected time.Duration
+	}{
+		{0, 0, time.Second},
+		{100 * time.Millisecond, 4, time.Second},
+		{2 * time.Second, 0, 2 * time.Second},
+		{2 * time.Second, 5, 6 * time.Second},
+		{2 * time.Second, 100, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := o.retryAfter(tt.latency, tt.queued); got != tt.expected {
+			t.Errorf("%v %d: got: %v, want %v", tt.latency, tt.queued, got, tt.expected)
+		}
+	}
+}
+
+// Shedding logic tested:
+// Requests over MaxInFlight wait in the queue, up to MaxQueue
+// Requests over the queue are rejected at once with a 503 and a Retry-After
+// Waiting requests are rejected after QueueTimeout
+// Waiting requests are served when a slot frees up
+func TestOverload(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	handler := (&Overload{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Minute}).Handler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-release
+		}))
+
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		return rec
+	}
+
+	go serve()
+	<-started
+
+	// Of two more requests, one is queued and the other is rejected
+	recs := make(chan *httptest.ResponseRecorder, 2)
+	for i := 0; i < 2; i++ {
+		go func() { recs <- serve() }()
+	}
+
+	rec := <-recs
+	if rec.Code != 503 || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("got: %d %v, want 503 with a Retry-After", rec.Code, rec.Header())
+	}
+
+	close(release)
+	if rec := <-recs; rec.Code != 200 {
+		t.Errorf("got: %d, want %d", rec.Code, 200)
+	}
+
+	// Timeout in the queue
+	block := make(chan struct{})
+	blocked := make(chan struct{})
+	defer close(block)
+	handler = (&Overload{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: 10 * time.Millisecond}).Handler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(blocked)
+			<-block
+		}))
+	go serve()
+	<-blocked
+
+	if rec := serve(); rec.Code != 503 {
+		t.Errorf("got: %d, want %d", rec.Code, 503)
+	}
+}
+
+func TestOverloadInvalidMaxInFlight(t *testing.T) {
+	for _, o := range []*Overload{{}, {MaxInFlight: -1, MaxQueue: 10}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v: expected a panic", o)
+				}
+			}()
+			o.Handler(http.NotFoundHandler())
+		}()
+	}
+}
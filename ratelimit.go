@@ -0,0 +1,72 @@
+package weberr
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimitError reports a request rejected because the client exceeded its quota.
+type RateLimitError struct {
+	// Limit is the number of requests allowed in the window
+	Limit int
+	// Remaining is the number of requests left in the window
+	Remaining int
+	// Reset is when the window ends and the quota is restored
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited: %d of %d requests remaining until %s", e.Remaining, e.Limit, e.Reset.UTC().Format(time.RFC3339))
+}
+
+// RateLimited creates a TooManyRequests error for a client that exceeded
+// its quota. Its response has the X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset (in Unix seconds) headers, and a Retry-After header
+// until reset, of at least a second, counted when the response is written.
+func RateLimited(limit, remaining int, reset time.Time) error {
+	err := error(&RateLimitError{Limit: limit, Remaining: remaining, Reset: reset})
+	if captureStack(TooManyRequests) {
+		err = errors.WithStack(err)
+	}
+
+	headers := http.Header{}
+	headers.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	headers.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	headers.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	return &Error{
+		error:       err,
+		errorType:   TooManyRequests,
+		userMessage: "Too many requests, please retry later",
+		headers:     headers,
+	}
+}
+
+// untilReset returns the delay until the window ends, of at least a second
+func (e *RateLimitError) untilReset() time.Duration {
+	if d := time.Until(e.Reset); d > time.Second {
+		return d
+	}
+
+	return time.Second
+}
+
+// GetRateLimit returns the RateLimitError of an error created by RateLimited, or nil.
+func GetRateLimit(err error) *RateLimitError {
+	var rateErr *RateLimitError
+	if stderrors.As(err, &rateErr) {
+		return rateErr
+	}
+
+	return nil
+}
+
+// IsRateLimited reports whether err was created by RateLimited.
+func IsRateLimited(err error) bool {
+	return GetRateLimit(err) != nil
+}
@@ -0,0 +1,66 @@
+package weberr
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).Truncate(time.Second)
+	err := Wrapf(RateLimited(100, 0, reset), "creating order")
+
+	if GetType(err) != TooManyRequests || GetUserMessage(err) == "" {
+		t.Errorf("got: %v %q", GetType(err), GetUserMessage(err))
+	}
+	if d := GetRetryAfter(err); d < 28*time.Second || d > 30*time.Second {
+		t.Errorf("got: %v, want about 30s", d)
+	}
+
+	// The delay is counted when it is asked for, not when the error is created
+	later := RateLimited(100, 0, time.Now().Add(time.Hour))
+	before := GetRetryAfter(later)
+	time.Sleep(10 * time.Millisecond)
+	if after := GetRetryAfter(Wrapf(later, "creating order")); after >= before || after < before-time.Second {
+		t.Errorf("got: %v, want less than %v", after, before)
+	}
+	if got := GetRetryAfter(WithRetryAfter(later, time.Minute)); got != time.Minute {
+		t.Errorf("got: %v, want %v", got, time.Minute)
+	}
+
+	limit := GetRateLimit(err)
+	if !IsRateLimited(err) || limit.Limit != 100 || limit.Remaining != 0 || !limit.Reset.Equal(reset) {
+		t.Errorf("got: %+v", limit)
+	}
+	if GetRateLimit(fmt.Errorf("creating order: %w", RateLimited(100, 0, reset))) == nil {
+		t.Errorf("expected the rate limit of an error wrapped with %%w")
+	}
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, nil, err)
+	expected := map[string]string{
+		"X-RateLimit-Limit":     "100",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+	}
+	for name, value := range expected {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("got: %s %q, want %q", name, got, value)
+		}
+	}
+	if rec.Code != 429 || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("got: %d %v", rec.Code, rec.Header())
+	}
+
+	if got := GetRetryAfter(RateLimited(10, 0, time.Now().Add(-time.Minute))); got != time.Second {
+		t.Errorf("got: %v, want %v", got, time.Second)
+	}
+	for _, err := range []error{nil, io.EOF, TooManyRequests.Errorf("msg")} {
+		if IsRateLimited(err) {
+			t.Errorf("%v: expected no rate limit", err)
+		}
+	}
+}
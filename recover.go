@@ -0,0 +1,183 @@
+package weberr
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PanicError describes a recovered panic.
+type PanicError struct {
+	// Value is the recovered value
+	Value interface{}
+	// Kind classifies the panic, e.g. "nil-map-write" or "index-out-of-range"
+	Kind string
+	// Frame is the frame that panicked
+	Frame runtime.Frame
+}
+
+func (p *PanicError) Error() string {
+	if err, ok := p.Value.(error); ok {
+		return "panic: " + err.Error()
+	}
+
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Unwrap returns the recovered value if it is an error
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// Fingerprint identifies panics of the same kind from the same function,
+// to group them in error reporting tools.
+func (p *PanicError) Fingerprint() string {
+	return p.Kind + "@" + p.Frame.Function
+}
+
+// Location returns the file and line that panicked, e.g. "handler.go:42"
+func (p *PanicError) Location() string {
+	if p.Frame.File == "" {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", path.Base(p.Frame.File), p.Frame.Line)
+}
+
+// panicKinds classify runtime errors by their message
+var panicKinds = []struct {
+	contains string
+	kind     string
+}{
+	{"assignment to entry in nil map", "nil-map-write"},
+	{"nil pointer dereference", "nil-pointer-dereference"},
+	{"index out of range", "index-out-of-range"},
+	{"slice bounds out of range", "slice-bounds-out-of-range"},
+	{"integer divide by zero", "divide-by-zero"},
+	{"interface conversion", "type-assertion"},
+	{"close of closed channel", "closed-channel"},
+	{"close of nil channel", "closed-channel"},
+	{"send on closed channel", "closed-channel"},
+}
+
+// classifyPanic returns the kind of a recovered value
+func classifyPanic(recovered interface{}) string {
+	runtimeErr, ok := recovered.(runtime.Error)
+	if !ok {
+		if _, ok := recovered.(error); ok {
+			return "error"
+		}
+		return "value"
+	}
+
+	msg := runtimeErr.Error()
+	for _, k := range panicKinds {
+		if strings.Contains(msg, k.contains) {
+			return k.kind
+		}
+	}
+
+	return "runtime-error"
+}
+
+// panicFrame returns the frame that panicked: the first frame outside the
+// runtime after runtime.gopanic. If the stack isn't the one of a panic, it
+// returns the caller of RecoverToError, the first frame of the stack.
+func panicFrame(st errors.StackTrace) runtime.Frame {
+	pcs := make([]uintptr, len(st))
+	for i, f := range st {
+		pcs[i] = uintptr(f)
+	}
+
+	var caller runtime.Frame
+	frames := runtime.CallersFrames(pcs)
+	for i, panicking := 0, false; ; i++ {
+		frame, more := frames.Next()
+
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			return frame
+		case i == 1:
+			caller = frame
+		}
+
+		if !more {
+			return caller
+		}
+	}
+}
+
+// GetPanic returns the PanicError of an error created by RecoverToError, or nil.
+func GetPanic(err error) *PanicError {
+	var p *PanicError
+	if stderrors.As(err, &p) {
+		return p
+	}
+
+	return nil
+}
+
+// RecoverToError converts a value returned by recover() into an
+// InternalServerError, with the panic value in its message.
+// The panic is classified, and the frame that panicked identified, in the
+// *PanicError returned by GetPanic.
+// It must be called from the deferred function that recovered, so that the
+// attached stack trace is the one of the panicking goroutine.
+// Returns nil if recovered is nil.
+func RecoverToError(recovered interface{}) error {
+	if recovered == nil {
+		return nil
+	}
+
+	p := &PanicError{
+		Value: recovered,
+		Kind:  classifyPanic(recovered),
+	}
+	err := errors.WithStack(p)
+	p.Frame = panicFrame(err.(stackTracer).StackTrace())
+
+	return &Error{
+		error:     err,
+		errorType: InternalServerError,
+	}
+}
+
+// Recover is an HTTP middleware that converts panics into errors,
+// written with WriteError.
+func Recover(next http.Handler) http.Handler {
+	return recoverer(next, WriteError)
+}
+
+// Recover is an HTTP middleware that converts panics into errors,
+// written with the writer.
+func (wr *Writer) Recover(next http.Handler) http.Handler {
+	return recoverer(next, wr.WriteError)
+}
+
+// recoverer calls next, and writes the error of any panic.
+// http.ErrAbortHandler is re-panicked, as it is meant to abort the response.
+func recoverer(next http.Handler, writeError func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			writeError(w, r, RecoverToError(recovered))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,165 @@
+package weberr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverToError(t *testing.T) {
+	tests := []struct {
+		recovered interface{}
+		expected  string
+	}{
+		{"boom", "panic: boom"},
+		{42, "panic: 42"},
+		{io.EOF, "panic: EOF"},
+		{NotFound.Errorf("gone"), "panic: gone"},
+	}
+	for _, tt := range tests {
+		err := RecoverToError(tt.recovered)
+		if err.Error() != tt.expected || GetType(err) != InternalServerError {
+			t.Errorf("got: %q %v, want %q %v", err, GetType(err), tt.expected, InternalServerError)
+		}
+	}
+
+	if RecoverToError(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+// panickingHandler panics with a nil map write
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	var m map[string]int
+	m["boom"]++
+}
+
+func TestRecover(t *testing.T) {
+	var recovered error
+	capture := Writer{Exposure: ExposeAll}
+	handler := recoverer(http.HandlerFunc(panickingHandler), func(w http.ResponseWriter, r *http.Request, err error) {
+		recovered = err
+		capture.WriteError(w, r, err)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != 500 {
+		t.Errorf("got: %d, want %d", rec.Code, 500)
+	}
+	if !strings.Contains(recovered.Error(), "assignment to entry in nil map") {
+		t.Errorf("got: %q", recovered)
+	}
+	if trace := GetStackTrace(recovered); !strings.Contains(trace, "panickingHandler") {
+		t.Errorf("expected the panic site in the trace, got: %s", trace)
+	}
+
+	rec = httptest.NewRecorder()
+	Recover(http.HandlerFunc(panickingHandler)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 500 || strings.Contains(rec.Body.String(), "nil map") {
+		t.Errorf("got: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	if !panics(func() { handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)) }) {
+		t.Errorf("expected http.ErrAbortHandler to be re-panicked")
+	}
+}
+
+// recovered returns the error RecoverToError makes of the panic of f
+func recovered(f func()) (err error) {
+	defer func() {
+		err = RecoverToError(recover())
+	}()
+	f()
+
+	return nil
+}
+
+func nilMapWrite() {
+	var m map[string]int
+	m["boom"]++
+}
+
+func indexOutOfRange() {
+	s := []int{}
+	i := 1
+	_ = s[i]
+}
+
+func nilPointerDereference() {
+	var p *struct{ n int }
+	p.n++
+}
+
+func panicWithValue() {
+	panic("boom")
+}
+
+func panicWithError() {
+	panic(io.EOF)
+}
+
+// Panic classification logic tested:
+// Runtime errors are classified by their message, other values by their type
+// The frame is the function that panicked, not the runtime
+// Panics from the same function and of the same kind share a fingerprint
+func TestPanicClassification(t *testing.T) {
+	tests := []struct {
+		f        func()
+		kind     string
+		function string
+	}{
+		{nilMapWrite, "nil-map-write", "nilMapWrite"},
+		{indexOutOfRange, "index-out-of-range", "indexOutOfRange"},
+		{nilPointerDereference, "nil-pointer-dereference", "nilPointerDereference"},
+		{panicWithValue, "value", "panicWithValue"},
+		{panicWithError, "error", "panicWithError"},
+	}
+	for _, tt := range tests {
+		p := GetPanic(recovered(tt.f))
+		if p == nil {
+			t.Fatalf("%s: expected a panic", tt.function)
+		}
+
+		if p.Kind != tt.kind || !strings.HasSuffix(p.Frame.Function, "."+tt.function) {
+			t.Errorf("got: %s in %s, want %s in %s", p.Kind, p.Frame.Function, tt.kind, tt.function)
+		}
+		if !strings.HasPrefix(p.Location(), "recover_test.go:") {
+			t.Errorf("got: %s, want recover_test.go", p.Location())
+		}
+		if p.Fingerprint() != GetPanic(recovered(tt.f)).Fingerprint() {
+			t.Errorf("expected identical fingerprints")
+		}
+	}
+
+	var target error = io.ErrUnexpectedEOF
+	if err := GetPanic(recovered(panicWithError)); !errors.Is(err, io.EOF) || errors.Is(err, target) {
+		t.Errorf("expected the recovered error to be unwrapped")
+	}
+
+	if GetPanic(fmt.Errorf("handling: %w", recovered(panicWithValue))) == nil {
+		t.Errorf("expected the panic of an error wrapped with %%w")
+	}
+
+	if GetPanic(io.EOF) != nil || GetPanic(Errorf("msg")) != nil {
+		t.Errorf("expected no panic")
+	}
+}
+
+func TestPanicFrameOutsidePanic(t *testing.T) {
+	p := GetPanic(RecoverToError("not panicking"))
+	if !strings.HasSuffix(p.Frame.Function, ".TestPanicFrameOutsidePanic") {
+		t.Errorf("got: %s, want the caller", p.Frame.Function)
+	}
+}
@@ -0,0 +1,62 @@
+package weberr
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// retryAfterer identifies an error with a retry delay hint
+type retryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// RetryAfter returns the delay after which the request may be retried.
+// Errors created by RateLimited without another delay are retried at the
+// reset of their window, counted from now.
+func (c *Error) RetryAfter() time.Duration {
+	if c.retryAfter == 0 {
+		if rateErr := GetRateLimit(c.error); rateErr != nil {
+			return rateErr.untilReset()
+		}
+	}
+
+	return c.retryAfter
+}
+
+// retryAfterHint returns the delay set on err, not the one derived from its
+// rate limit, so that the errors created from it keep deriving it
+func retryAfterHint(err error) time.Duration {
+	if c, ok := err.(*Error); ok {
+		return c.retryAfter
+	}
+
+	return GetRetryAfter(err)
+}
+
+// GetRetryAfter returns the delay after which the failed request may be
+// retried, for all errors. The Writer sends it as a Retry-After header.
+// If error is not `retryAfterer` returns 0.
+func GetRetryAfter(err error) time.Duration {
+	if retryErr, ok := err.(retryAfterer); ok {
+		return retryErr.RetryAfter()
+	}
+
+	return 0
+}
+
+// WithRetryAfter sets the delay after which the failed request may be retried.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	c := inherit(err)
+	c.retryAfter = d
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
@@ -0,0 +1,50 @@
+package weberr
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Retry delay logic tested:
+// Default: no delay
+// Delay is preserved by wrapping
+// Delay is sent as a Retry-After header, rounded up to the second
+func TestGetRetryAfter(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected time.Duration
+	}{
+		{nil, 0},
+		{io.EOF, 0},
+		{WithRetryAfter(nil, time.Second), 0},
+		{WithRetryAfter(io.EOF, time.Second), time.Second},
+		{Wrapf(WithRetryAfter(io.EOF, time.Second), "msg"), time.Second},
+		{UserWrapf(WithRetryAfter(io.EOF, time.Second), "msg"), time.Second},
+		{AddDetails(WithRetryAfter(io.EOF, time.Second), "foo"), time.Second},
+		{ServiceUnavailable.Set(WithRetryAfter(io.EOF, time.Second)), time.Second},
+		{SetUserMessage(WithRetryAfter(io.EOF, time.Second), "msg"), time.Second},
+		{SetCode(WithRetryAfter(io.EOF, time.Second), "A"), time.Second},
+		{WithSeverity(WithRetryAfter(io.EOF, time.Second), SeverityInfo), time.Second},
+		{New("msg").Wrap(WithRetryAfter(io.EOF, time.Second)).Err(), time.Second},
+	}
+	for _, tt := range tests {
+		got := GetRetryAfter(tt.err)
+		if got != tt.expected {
+			t.Errorf("got: %v, want %v", got, tt.expected)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, nil, WithRetryAfter(ServiceUnavailable.Errorf("busy"), 1500*time.Millisecond))
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("got: %q, want %q", got, "2")
+	}
+
+	rec = httptest.NewRecorder()
+	WriteError(rec, nil, ServiceUnavailable.Errorf("busy"))
+	if got := rec.Header().Get("Retry-After"); got != "" {
+		t.Errorf("got: %q, want no header", got)
+	}
+}
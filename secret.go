@@ -0,0 +1,160 @@
+package weberr
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+)
+
+// redacted is what secrets are rendered as
+const redacted = "***"
+
+// Secret is a detail value that must not be logged or rendered, e.g. a token.
+// It renders as *** with fmt, encoding/json, encoding/xml and log/slog,
+// wherever it is, e.g. in a map detail:
+//
+//	err = weberr.AddDetails(err, map[string]interface{}{
+//		"user":  userID,
+//		"token": weberr.NewSecret(token),
+//	})
+//
+// WithoutSecrets removes secrets from the details of an error instead, e.g.
+// before sending it to an error tracker.
+type Secret struct {
+	value interface{}
+}
+
+// NewSecret returns value marked as secret.
+func NewSecret(value interface{}) Secret {
+	return Secret{value: value}
+}
+
+// Value returns the secret value.
+func (s Secret) Value() interface{} { return s.value }
+
+// String returns ***
+func (s Secret) String() string { return redacted }
+
+// Format formats *** for all verbs
+func (s Secret) Format(f fmt.State, verb rune) { io.WriteString(f, redacted) }
+
+// MarshalText returns ***, for encoding/json and encoding/xml
+func (s Secret) MarshalText() ([]byte, error) { return []byte(redacted), nil }
+
+// LogValue returns ***, for log/slog
+func (s Secret) LogValue() slog.Value { return slog.StringValue(redacted) }
+
+// WithSecretDetail adds a detail holding a secret value, rendered as ***:
+// a map from key to the value.
+func WithSecretDetail(err error, key string, value interface{}) error {
+	return AddDetails(err, map[string]interface{}{key: NewSecret(value)})
+}
+
+// WithoutSecrets returns err without its secret details, and without the
+// secret values of its map and slice details, however nested, e.g. in a
+// map[string]weberr.Secret. Map and slice details left empty are dropped.
+// It returns err itself if it has none.
+// It can be used as a Hook, to drop secrets from responses.
+func WithoutSecrets(err error) error {
+	details := GetDetails(err)
+
+	var kept []interface{}
+	changed := false
+	for _, detail := range details {
+		v := reflect.ValueOf(detail)
+		if isSecret(v) {
+			changed = true
+			continue
+		}
+		if clean, ok := withoutSecretValues(v); ok {
+			changed = true
+			if clean.Len() == 0 {
+				continue
+			}
+			detail = clean.Interface()
+		}
+		kept = append(kept, detail)
+	}
+	if !changed {
+		return err
+	}
+
+	c := inherit(err)
+	c.details = kept
+
+	return c
+}
+
+// secretType is the type of the values WithoutSecrets drops
+var secretType = reflect.TypeOf(Secret{})
+
+// isSecret reports whether v is a Secret, in an interface or not
+func isSecret(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v.IsValid() && v.Type() == secretType
+}
+
+// mayHoldSecrets reports whether the elements of type t can be or hold secrets
+func mayHoldSecrets(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map, reflect.Slice:
+		return true
+	}
+
+	return t == secretType
+}
+
+// withoutSecretValues returns a copy of the map or slice v without its secret
+// values, however nested, and whether it had any. Other values are kept as is.
+func withoutSecretValues(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if !mayHoldSecrets(v.Type().Elem()) {
+			return v, false
+		}
+
+		kept := reflect.MakeMapWithSize(v.Type(), v.Len())
+		changed := false
+		for iter := v.MapRange(); iter.Next(); {
+			if isSecret(iter.Value()) {
+				changed = true
+				continue
+			}
+			value, ok := withoutSecretValues(iter.Value())
+			changed = changed || ok
+			kept.SetMapIndex(iter.Key(), value)
+		}
+		if changed {
+			return kept, true
+		}
+	case reflect.Slice:
+		if !mayHoldSecrets(v.Type().Elem()) {
+			return v, false
+		}
+
+		kept := reflect.MakeSlice(v.Type(), 0, v.Len())
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			if isSecret(v.Index(i)) {
+				changed = true
+				continue
+			}
+			value, ok := withoutSecretValues(v.Index(i))
+			changed = changed || ok
+			kept = reflect.Append(kept, value)
+		}
+		if changed {
+			return kept, true
+		}
+	}
+
+	return v, false
+}
@@ -0,0 +1,95 @@
+package weberr
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	s := NewSecret("s3cr3t")
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%d"} {
+		if got := fmt.Sprintf(format, s); got != "***" {
+			t.Errorf("%s: got: %q, want ***", format, got)
+		}
+	}
+	if got := fmt.Sprint(map[string]interface{}{"token": s}); got != "map[token:***]" {
+		t.Errorf("got: %q", got)
+	}
+	if data, _ := json.Marshal(map[string]interface{}{"token": s}); string(data) != `{"token":"***"}` {
+		t.Errorf("got: %s", data)
+	}
+	type doc struct {
+		Token Secret
+	}
+	if data, _ := xml.Marshal(doc{s}); string(data) != "<doc><Token>***</Token></doc>" {
+		t.Errorf("got: %s", data)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("msg", "token", s)
+	if !strings.Contains(buf.String(), "token=***") {
+		t.Errorf("got: %q", buf.String())
+	}
+
+	if s.Value() != "s3cr3t" {
+		t.Errorf("got: %v, want the secret value", s.Value())
+	}
+}
+
+// Secret detail logic tested:
+// Secret details are rendered as ***
+// WithoutSecrets drops secret details and secret map values, keeping the rest
+// Secrets are dropped from maps and slices of any type, however nested
+func TestWithSecretDetail(t *testing.T) {
+	err := WithSecretDetail(AddDetails(NotFound.UserWrapf(io.EOF, "Not here"), "order"), "token", "s3cr3t")
+
+	rec := httptest.NewRecorder()
+	wr := Writer{Exposure: ExposeDetails}
+	wr.WriteError(rec, nil, err)
+	if got := rec.Body.String(); strings.Contains(got, "s3cr3t") || !strings.Contains(got, `{"token":"***"}`) {
+		t.Errorf("got: %s", got)
+	}
+	if got := fmt.Sprint(GetDetails(err)); strings.Contains(got, "s3cr3t") {
+		t.Errorf("got: %s", got)
+	}
+
+	err = AddDetails(AddDetails(err, NewSecret("s3cr3t")), map[string]interface{}{"user": 42, "password": NewSecret("pw")})
+	clean := WithoutSecrets(err)
+	details := GetDetails(clean)
+	if len(details) != 2 || details[0] != "order" || fmt.Sprint(details[1]) != "map[user:42]" {
+		t.Errorf("got: %v", details)
+	}
+	if GetType(clean) != NotFound || GetUserMessage(clean) != "Not here" || len(GetDetails(err)) != 4 {
+		t.Errorf("got: %v %q, want the other attributes kept, and err unchanged", GetType(clean), GetUserMessage(clean))
+	}
+
+	tests := []struct {
+		detail   interface{}
+		expected string
+	}{
+		{map[string]Secret{"token": NewSecret("s3cr3t")}, "[]"},
+		{map[string]interface{}{"user": 42, "auth": map[string]Secret{"token": NewSecret("s3cr3t")}}, "[map[auth:map[] user:42]]"},
+		{[]interface{}{"a", NewSecret("s3cr3t"), []Secret{NewSecret("pw")}}, "[[a []]]"},
+		{map[string][]interface{}{"keys": {1, NewSecret("s3cr3t")}}, "[map[keys:[1]]]"},
+		{[]string{"a"}, "[[a]]"},
+	}
+	for _, tt := range tests {
+		clean := WithoutSecrets(AddDetails(io.EOF, tt.detail))
+		if got := fmt.Sprint(GetDetails(clean)); got != tt.expected {
+			t.Errorf("got: %s, want %s", got, tt.expected)
+		}
+	}
+
+	plain := AddDetails(AddDetails(io.EOF, "order"), map[string][]string{"ids": {"1"}})
+	if WithoutSecrets(plain) != plain || WithoutSecrets(nil) != nil {
+		t.Errorf("expected the error itself without secrets")
+	}
+}
@@ -0,0 +1,36 @@
+package weberr
+
+// SentinelError is an error identified by its code, to be declared once and
+// compared with errors.Is:
+//
+//	var ErrQuotaExceeded = weberr.TooManyRequests.Sentinel("billing.QUOTA_EXCEEDED")
+//	...
+//	return weberr.UserWrapf(ErrQuotaExceeded, "Quota of %d orders exceeded", quota)
+//	...
+//	if errors.Is(err, ErrQuotaExceeded) {
+//
+// Errors wrapping a sentinel get its type and code, and errors with its code
+// match it, also when reconstructed from responses (see FromResponse).
+// Sentinels are comparable: sentinels with the same code and type are equal.
+type SentinelError struct {
+	code      Code
+	errorType ErrorType
+}
+
+func (e SentinelError) Error() string { return string(e.code) }
+
+// Code returns the code of the sentinel
+func (e SentinelError) Code() Code { return e.code }
+
+// Type returns the type of the sentinel
+func (e SentinelError) Type() ErrorType { return e.errorType }
+
+// Sentinel returns the sentinel error of code, of this type.
+func (errorType ErrorType) Sentinel(code Code) SentinelError {
+	return SentinelError{code: code, errorType: errorType}
+}
+
+// Sentinel returns the sentinel error of code, without type.
+func Sentinel(code Code) SentinelError {
+	return NoType.Sentinel(code)
+}
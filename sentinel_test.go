@@ -0,0 +1,56 @@
+package weberr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+var errQuotaExceeded = TooManyRequests.Sentinel("billing.QUOTA_EXCEEDED")
+
+func TestSentinel(t *testing.T) {
+	if errQuotaExceeded != TooManyRequests.Sentinel("billing.QUOTA_EXCEEDED") {
+		t.Errorf("expected sentinels with the same code to be equal")
+	}
+	if errQuotaExceeded == Sentinel("billing.QUOTA_EXCEEDED") || errors.Is(Sentinel("other"), errQuotaExceeded) {
+		t.Errorf("expected sentinels with different types or codes to differ")
+	}
+	if errQuotaExceeded.Error() != "billing.QUOTA_EXCEEDED" || GetType(errQuotaExceeded) != TooManyRequests {
+		t.Errorf("got: %q %v", errQuotaExceeded, GetType(errQuotaExceeded))
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"sentinel", errQuotaExceeded},
+		{"Wrapf", Wrapf(errQuotaExceeded, "msg")},
+		{"UserWrapf", UserWrapf(UserWrapf(errQuotaExceeded, "Quota exceeded"), "Can't create order")},
+		{"ErrorType.UserWrapf", BadRequest.UserWrapf(errQuotaExceeded, "Quota exceeded")},
+		{"AddDetails", AddDetails(errQuotaExceeded, "detail")},
+		{"SetUserMessage", SetUserMessage(errQuotaExceeded, "msg")},
+		{"WithRetryAfter", WithRetryAfter(errQuotaExceeded, time.Second)},
+		{"WithType", WithType(errQuotaExceeded, Forbidden)},
+		{"fmt", fmt.Errorf("creating order: %w", UserWrapf(errQuotaExceeded, "msg"))},
+		{"by code", SetCode(NoType.Errorf("msg"), "billing.QUOTA_EXCEEDED")},
+		{"decoded", FromJSON([]byte(`{"status":429,"code":"billing.QUOTA_EXCEEDED","message":"msg"}`))},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, errQuotaExceeded) {
+			t.Errorf("%s: expected %v to match the sentinel", tt.name, tt.err)
+		}
+	}
+
+	err := UserWrapf(errQuotaExceeded, "Quota exceeded")
+	if GetType(err) != TooManyRequests || GetCode(err) != "billing.QUOTA_EXCEEDED" {
+		t.Errorf("got: %v %q, want the type and code of the sentinel", GetType(err), GetCode(err))
+	}
+
+	for _, err := range []error{io.EOF, Wrapf(io.EOF, "msg"), SetCode(Errorf("msg"), "billing.OTHER")} {
+		if errors.Is(err, errQuotaExceeded) {
+			t.Errorf("%v: expected no match", err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package weberr
+
+import (
+	"log/slog"
+
+	"github.com/pkg/errors"
+)
+
+// Severity is the level at which an error should be logged.
+type Severity uint
+
+const (
+	// NoSeverity is the placeholder for errors without an explicit severity.
+	// GetSeverity derives the severity of such errors from their type.
+	NoSeverity Severity = iota
+	// SeverityDebug is for errors only useful while debugging
+	SeverityDebug
+	// SeverityInfo is for expected errors, such as client errors
+	SeverityInfo
+	// SeverityWarning is for unexpected errors that don't need attention
+	SeverityWarning
+	// SeverityError is for errors that need attention
+	SeverityError
+	// SeverityCritical is for errors that need immediate attention
+	SeverityCritical
+)
+
+// severityNames are the names of the severities
+var severityNames = map[Severity]string{
+	NoSeverity:       "none",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// SlogLevel returns the slog level matching the severity.
+// SeverityCritical is mapped above slog.LevelError.
+func (s Severity) SlogLevel() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
+
+// severer identifies an error with a severity
+type severer interface {
+	Severity() Severity
+}
+
+// Severity returns the explicit severity of the error
+func (c *Error) Severity() Severity { return c.severity }
+
+// getSeverity returns the explicit severity of err, or NoSeverity
+func getSeverity(err error) Severity {
+	if sevErr, ok := err.(severer); ok {
+		return sevErr.Severity()
+	}
+
+	return NoSeverity
+}
+
+// GetSeverity returns the severity of all errors.
+// If no severity was set with WithSeverity, it is derived from the type:
+// client errors (4xx) are SeverityInfo, others SeverityError.
+func GetSeverity(err error) Severity {
+	if err == nil {
+		return NoSeverity
+	}
+
+	if severity := getSeverity(err); severity != NoSeverity {
+		return severity
+	}
+
+	if status := GetType(err).HTTPStatus(); status >= 400 && status < 500 {
+		return SeverityInfo
+	}
+
+	return SeverityError
+}
+
+// WithSeverity sets the severity of an error, preserved by wrapping.
+func WithSeverity(err error, severity Severity) error {
+	if err == nil {
+		return nil
+	}
+
+	c := inherit(err)
+	c.severity = severity
+
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
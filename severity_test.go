@@ -0,0 +1,66 @@
+package weberr
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// Severity logic tested:
+// Default: derived from the type
+// Explicit severity survives wrapping
+// Explicit severity is overridden by the top most WithSeverity
+func TestGetSeverity(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected Severity
+	}{
+		{nil, NoSeverity},
+		{io.EOF, SeverityError},
+		{Errorf("msg"), SeverityError},
+		{NotFound.Errorf("msg"), SeverityInfo},
+		{TooManyRequests.Errorf("msg"), SeverityInfo},
+		{ServiceUnavailable.Errorf("msg"), SeverityError},
+		{WithSeverity(nil, SeverityDebug), NoSeverity},
+		{WithSeverity(NotFound.Errorf("msg"), SeverityWarning), SeverityWarning},
+		{Wrapf(WithSeverity(io.EOF, SeverityCritical), "msg"), SeverityCritical},
+		{UserWrapf(WithSeverity(io.EOF, SeverityCritical), "msg"), SeverityCritical},
+		{AddDetails(WithSeverity(io.EOF, SeverityCritical), "foo"), SeverityCritical},
+		{BadRequest.Set(WithSeverity(io.EOF, SeverityCritical)), SeverityCritical},
+		{SetUserMessage(WithSeverity(io.EOF, SeverityCritical), "msg"), SeverityCritical},
+		{SetCode(WithSeverity(io.EOF, SeverityCritical), "A"), SeverityCritical},
+		{New("msg").Wrap(WithSeverity(io.EOF, SeverityCritical)).Err(), SeverityCritical},
+		{WithSeverity(WithSeverity(io.EOF, SeverityCritical), SeverityDebug), SeverityDebug},
+	}
+	for _, tt := range tests {
+		got := GetSeverity(tt.err)
+		if got != tt.expected {
+			t.Errorf("got: %v, want %v", got, tt.expected)
+		}
+	}
+
+	err := WithSeverity(SetCode(NotFound.UserErrorf("Not here"), "A"), SeverityWarning)
+	if GetType(err) != NotFound || GetUserMessage(err) != "Not here" || GetCode(err) != "A" {
+		t.Errorf("got: %v %q %q", GetType(err), GetUserMessage(err), GetCode(err))
+	}
+}
+
+func TestSeverityLevels(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		name     string
+		level    slog.Level
+	}{
+		{SeverityDebug, "debug", slog.LevelDebug},
+		{SeverityInfo, "info", slog.LevelInfo},
+		{SeverityWarning, "warning", slog.LevelWarn},
+		{SeverityError, "error", slog.LevelError},
+		{SeverityCritical, "critical", slog.LevelError + 4},
+		{Severity(42), "unknown", slog.LevelError},
+	}
+	for _, tt := range tests {
+		if tt.severity.String() != tt.name || tt.severity.SlogLevel() != tt.level {
+			t.Errorf("got: %s %v, want %s %v", tt.severity, tt.severity.SlogLevel(), tt.name, tt.level)
+		}
+	}
+}
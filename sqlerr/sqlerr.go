@@ -0,0 +1,91 @@
+// Package sqlerr classifies database errors into weberr errors, so that
+// repository layers don't have to map them:
+//
+//	err := db.QueryRowContext(ctx, query, id).Scan(&order.ID, &order.Name)
+//	if err != nil {
+//		return nil, sqlerr.Classify(err)
+//	}
+//
+// PostgreSQL errors of both github.com/lib/pq and github.com/jackc/pgx are
+// classified by their SQLSTATE code.
+package sqlerr
+
+import (
+	"database/sql"
+	stderrors "errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+
+	"github.com/zgalor/weberr"
+)
+
+// PostgreSQL error codes
+const (
+	notNullViolation    = "23502"
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+)
+
+// class is the weberr type and user message of a PostgreSQL error code
+type class struct {
+	errorType   weberr.ErrorType
+	userMessage string
+}
+
+var classes = map[string]class{
+	notNullViolation:    {weberr.BadRequest, "A required value is missing"},
+	foreignKeyViolation: {weberr.BadRequest, "The request references a resource that doesn't exist"},
+	uniqueViolation:     {weberr.Conflict, "The resource already exists"},
+	checkViolation:      {weberr.BadRequest, "The request is invalid"},
+}
+
+// Classify converts a database error into a weberr error wrapping it:
+// sql.ErrNoRows is NotFound, unique violations are Conflict, and not null,
+// foreign key and check violations are BadRequest. The name of the violated
+// constraint is added as a map[string]string detail with the "constraint"
+// key.
+// Other errors are returned unchanged.
+func Classify(err error) error {
+	for _, e := range weberr.Chain(err) {
+		if e == sql.ErrNoRows {
+			return weberr.NotFound.UserWrapf(err, "The resource was not found")
+		}
+	}
+
+	code, constraint, ok := postgresError(err)
+	if !ok {
+		return err
+	}
+
+	c, ok := classes[code]
+	if !ok {
+		return err
+	}
+
+	err = c.errorType.UserWrapf(err, "%s", c.userMessage)
+	if constraint != "" {
+		err = weberr.AddDetails(err, map[string]string{"constraint": constraint})
+	}
+
+	return err
+}
+
+// postgresError returns the code and constraint name of a PostgreSQL error
+// from one of the supported drivers
+func postgresError(err error) (code, constraint string, ok bool) {
+	for _, e := range weberr.Chain(err) {
+		var pgErr *pgconn.PgError
+		if stderrors.As(e, &pgErr) {
+			return pgErr.Code, pgErr.ConstraintName, true
+		}
+
+		var pqErr *pq.Error
+		if stderrors.As(e, &pqErr) {
+			return string(pqErr.Code), pqErr.Constraint, true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,54 @@
+package sqlerr
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+
+	"github.com/zgalor/weberr"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err        error
+		errType    weberr.ErrorType
+		constraint string
+	}{
+		{nil, weberr.NoType, ""},
+		{io.EOF, weberr.NoType, ""},
+		{sql.ErrNoRows, weberr.NotFound, ""},
+		{fmt.Errorf("scanning order: %w", sql.ErrNoRows), weberr.NotFound, ""},
+		{weberr.Wrapf(sql.ErrNoRows, "scanning order"), weberr.NotFound, ""},
+		{&pgconn.PgError{Code: "23505", ConstraintName: "orders_name_key"}, weberr.Conflict, "orders_name_key"},
+		{&pq.Error{Code: "23505", Constraint: "orders_name_key"}, weberr.Conflict, "orders_name_key"},
+		{weberr.Wrapf(&pq.Error{Code: "23503", Constraint: "orders_user_fkey"}, "inserting"), weberr.BadRequest, "orders_user_fkey"},
+		{fmt.Errorf("inserting: %w", &pgconn.PgError{Code: "23514", ConstraintName: "quantity_positive"}), weberr.BadRequest, "quantity_positive"},
+		{&pgconn.PgError{Code: "23502"}, weberr.BadRequest, ""},
+		{&pgconn.PgError{Code: "42P01"}, weberr.NoType, ""},
+	}
+	for _, tt := range tests {
+		got := Classify(tt.err)
+		if tt.errType == weberr.NoType {
+			if got != tt.err {
+				t.Errorf("got: %v, want %v unchanged", got, tt.err)
+			}
+			continue
+		}
+
+		if weberr.GetType(got) != tt.errType || weberr.GetUserMessage(got) == "" || got.Error() != tt.err.Error() {
+			t.Errorf("%v: got: %v %q %q, want %v", tt.err, weberr.GetType(got), weberr.GetUserMessage(got), got, tt.errType)
+		}
+
+		details := weberr.GetDetails(got)
+		if tt.constraint == "" && len(details) != 0 {
+			t.Errorf("got: %v, want no details", details)
+		}
+		if tt.constraint != "" && (len(details) != 1 || details[0].(map[string]string)["constraint"] != tt.constraint) {
+			t.Errorf("got: %v, want constraint %q", details, tt.constraint)
+		}
+	}
+}
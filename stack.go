@@ -0,0 +1,258 @@
+package weberr
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// stackDisabled holds the map[ErrorType]bool of types created without stack traces
+	stackDisabled atomic.Value
+	// stackDisabledMu serializes updates of stackDisabled
+	stackDisabledMu sync.Mutex
+)
+
+// SetStackCapture enables or disables capturing stack traces for errors of a type.
+// Capture is enabled for all types by default. Disabling it makes creating and
+// wrapping errors of the type cheaper, e.g. for BadRequest errors produced by
+// request validation in hot paths, where stack traces are of little use.
+func SetStackCapture(errorType ErrorType, enabled bool) {
+	stackDisabledMu.Lock()
+	defer stackDisabledMu.Unlock()
+
+	disabled := map[ErrorType]bool{}
+	if current, ok := stackDisabled.Load().(map[ErrorType]bool); ok {
+		for t := range current {
+			disabled[t] = true
+		}
+	}
+
+	if enabled {
+		delete(disabled, errorType)
+	} else {
+		disabled[errorType] = true
+	}
+	stackDisabled.Store(disabled)
+}
+
+// captureStack reports whether stack traces are captured for errorType
+func captureStack(errorType ErrorType) bool {
+	disabled, _ := stackDisabled.Load().(map[ErrorType]bool)
+	return !disabled[errorType]
+}
+
+// plainErrorf returns an error with formatted string, without a stack trace
+func plainErrorf(msg string, args ...interface{}) error {
+	return stderrors.New(fmt.Sprintf(msg, args...))
+}
+
+// forceStack makes wrapping capture a stack trace even if the wrapped error has one
+var forceStack atomic.Bool
+
+// SetForceStackCapture makes wrapping functions capture a new stack trace
+// even when the wrapped error already has one.
+// By default they only capture a stack trace for errors that don't have one
+// yet, as GetStackTrace only shows the innermost one anyway.
+func SetForceStackCapture(force bool) {
+	forceStack.Store(force)
+}
+
+// needsStack reports whether wrapping err with errorType should capture a stack trace
+func needsStack(errorType ErrorType, err error) bool {
+	return captureStack(errorType) && (forceStack.Load() || !hasStackTrace(err))
+}
+
+// hasStackTrace reports whether err, or an error it wraps, has a stack trace
+func hasStackTrace(err error) bool {
+	for err != nil {
+		if _, ok := err.(stackTracer); ok {
+			return true
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+
+	return false
+}
+
+// internalPackages are the packages creating and wrapping errors, whose frames
+// are trimmed from the top of stack traces
+var internalPackages = []string{
+	"github.com/zgalor/weberr",
+	"github.com/zgalor/weberr/chiadapter",
+	"github.com/zgalor/weberr/cloud",
+	"github.com/zgalor/weberr/echoadapter",
+	"github.com/zgalor/weberr/errors",
+	"github.com/zgalor/weberr/ginadapter",
+	"github.com/zgalor/weberr/gqladapter",
+	"github.com/zgalor/weberr/k8s",
+	"github.com/zgalor/weberr/sqlerr",
+	"github.com/zgalor/weberr/validation",
+	"github.com/pkg/errors",
+}
+
+// trimStack removes the frames of the packages creating and wrapping errors
+// from the top of st, so that it starts with the code that created the error,
+// however many helpers of the package were called.
+// All frames are kept if none is external.
+func trimStack(st errors.StackTrace) errors.StackTrace {
+	for i, frame := range st {
+		if !internalFrame(frame) {
+			return st[i:]
+		}
+	}
+
+	return st
+}
+
+// internalFrame reports whether a frame belongs to one of internalPackages
+func internalFrame(frame errors.Frame) bool {
+	fn, _ := frameFunction(frame)
+	if fn == "" {
+		return false
+	}
+
+	// The package path ends at the first dot after the last slash
+	pkg := fn
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		if j := strings.Index(fn[i:], "."); j >= 0 {
+			pkg = fn[:i+j]
+		}
+	}
+
+	for _, internal := range internalPackages {
+		if pkg == internal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// frameFunction returns the full name of the function and the file of a frame
+func frameFunction(frame errors.Frame) (string, string) {
+	// Frames hold the return address of the calls
+	pc := uintptr(frame) - 1
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "", ""
+	}
+	file, _ := fn.FileLine(pc)
+
+	return fn.Name(), file
+}
+
+var (
+	// maxStackDepth is the number of frames GetStackTrace renders, 0 for all
+	maxStackDepth atomic.Int32
+	// stackFilter holds the []string of function prefixes GetStackTrace omits
+	stackFilter atomic.Value
+)
+
+// SetMaxStackDepth limits the number of frames GetStackTrace renders, so that
+// traces in logs stay short. Zero, the default, renders all the frames.
+// The limit applies after filtering, to the frames closest to the origin of
+// the error.
+func SetMaxStackDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	maxStackDepth.Store(int32(depth))
+}
+
+// SetStackFrameFilter makes GetStackTrace omit the frames of the functions
+// whose full name starts with one of prefixes, e.g. "runtime." or "net/http."
+// for the frames of the HTTP server, or the import path of middleware
+// packages. It replaces the prefixes previously set.
+func SetStackFrameFilter(prefixes ...string) {
+	stackFilter.Store(append([]string(nil), prefixes...))
+}
+
+// filterStack removes the frames omitted by the filter from st and applies
+// the depth limit
+func filterStack(st errors.StackTrace) errors.StackTrace {
+	prefixes, _ := stackFilter.Load().([]string)
+	if len(prefixes) > 0 {
+		filtered := make(errors.StackTrace, 0, len(st))
+		for _, frame := range st {
+			if !filteredFrame(frame, prefixes) {
+				filtered = append(filtered, frame)
+			}
+		}
+		st = filtered
+	}
+
+	if depth := int(maxStackDepth.Load()); depth > 0 && len(st) > depth {
+		st = st[:depth]
+	}
+
+	return st
+}
+
+// filteredFrame reports whether the function of a frame starts with one of prefixes
+func filteredFrame(frame errors.Frame, prefixes []string) bool {
+	fn, _ := frameFunction(frame)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(fn, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StackFrame is a frame of a stack trace.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// GetStackFrames returns the frames of the stack trace of err, as
+// GetStackTrace renders them, or nil if err has no stack trace.
+func GetStackFrames(err error) []StackFrame {
+	x, ok := baseStackTracer(err).(stackTracer)
+	if !ok {
+		return nil
+	}
+
+	st := filterStack(trimStack(x.StackTrace()))
+	if len(st) == 0 {
+		return nil
+	}
+
+	frames := make([]StackFrame, len(st))
+	for i, frame := range st {
+		// Frames hold the return address of the calls
+		pc := uintptr(frame) - 1
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			frames[i].Function = fn.Name()
+			frames[i].File, frames[i].Line = fn.FileLine(pc)
+		}
+	}
+
+	return frames
+}
+
+// GetStackTraceJSON returns the stack trace of err as a JSON array of
+// {"function", "file", "line"} objects, for log aggregators to index.
+// Errors without a stack trace have an empty array.
+func GetStackTraceJSON(err error) ([]byte, error) {
+	frames := GetStackFrames(err)
+	if frames == nil {
+		frames = []StackFrame{}
+	}
+
+	return json.Marshal(frames)
+}
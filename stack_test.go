@@ -0,0 +1,165 @@
+package weberr
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Stack capture logic tested:
+// Capture is enabled by default
+// NoStack variants never capture
+// Disabling capture for a type applies to all constructors resolving to that type
+func TestSetStackCapture(t *testing.T) {
+	defer SetStackCapture(BadRequest, true)
+
+	if !hasStackTrace(BadRequest.Errorf("msg")) || hasStackTrace(BadRequest.ErrorfNoStack("msg")) ||
+		hasStackTrace(BadRequest.UserErrorfNoStack("msg")) {
+		t.Errorf("unexpected stack capture with default settings")
+	}
+
+	SetStackCapture(BadRequest, false)
+
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{BadRequest.Errorf("msg"), false},
+		{BadRequest.UserErrorf("msg"), false},
+		{BadRequest.Wrapf(io.EOF, "msg"), false},
+		{Wrapf(BadRequest.ErrorfNoStack("msg"), "msg"), false},
+		{UserWrapf(BadRequest.ErrorfNoStack("msg"), "msg"), false},
+		{BadRequest.UserWrapf(io.EOF, "msg"), false},
+		{BadRequest.AddDetails(io.EOF, "foo"), false},
+		{BadRequest.AddDetails(nil, "foo"), false},
+		{BadRequest.Set(io.EOF), false},
+		{BadRequest.SetUserMessage(io.EOF, "msg"), false},
+		{BadRequest.SetCode(io.EOF, "A"), false},
+		{New("msg").Type(BadRequest).Err(), false},
+		{NotFound.Errorf("msg"), true},
+		{NotFound.Wrapf(BadRequest.ErrorfNoStack("msg"), "msg"), true},
+		{Errorf("msg"), true},
+	}
+	for i, tt := range tests {
+		if got := hasStackTrace(tt.err); got != tt.expected {
+			t.Errorf("case %d: got: %v, want %v", i, got, tt.expected)
+		}
+	}
+
+	err := BadRequest.Wrapf(BadRequest.UserErrorf("user"), "internal")
+	if err.Error() != "internal: user" || GetUserMessage(err) != "user" || GetType(err) != BadRequest {
+		t.Errorf("got: %q %q %v", err, GetUserMessage(err), GetType(err))
+	}
+	if got := GetStackTrace(err); got != "internal: user" {
+		t.Errorf("got: %q, want %q", got, "internal: user")
+	}
+
+	SetStackCapture(BadRequest, true)
+	if !hasStackTrace(BadRequest.Errorf("msg")) {
+		t.Errorf("expected a stack trace once re-enabled")
+	}
+}
+
+// stackCount returns the number of stack traces of err's chain
+func stackCount(err error) int {
+	count := 0
+	for err != nil {
+		if _, ok := err.(stackTracer); ok {
+			count++
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+
+	return count
+}
+
+// Wrapping logic tested:
+// Wrapping an error without a stack trace captures one
+// Wrapping an error with a stack trace only annotates it, unless forced
+// The message and trace are unaffected
+func TestWrapStackCapture(t *testing.T) {
+	defer SetForceStackCapture(false)
+
+	traced := Errorf("traced")
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{Wrapf(io.EOF, "msg"), 1},
+		{Wrapf(traced, "msg"), 1},
+		{Wrapf(Wrapf(Wrapf(traced, "1"), "2"), "3"), 1},
+		{UserWrapf(traced, "msg"), 1},
+		{AddDetails(traced, "foo"), 1},
+		{NotFound.Set(traced), 1},
+		{SetUserMessage(traced, "msg"), 1},
+		{SetCode(traced, "A"), 1},
+		{WithSeverity(traced, SeverityInfo), 1},
+		{WithRetryAfter(traced, 1), 1},
+		{New("msg").Wrap(traced).Err(), 1},
+		{New("msg").Wrap(io.EOF).Err(), 1},
+	}
+	for i, tt := range tests {
+		if got := stackCount(tt.err); got != tt.expected {
+			t.Errorf("case %d: got: %d stack traces, want %d", i, got, tt.expected)
+		}
+	}
+
+	err := Wrapf(Wrapf(traced, "1"), "2")
+	if err.Error() != "2: 1: traced" || GetStackTrace(err) != GetStackTrace(traced) {
+		t.Errorf("got: %q\n%s", err, GetStackTrace(err))
+	}
+
+	SetForceStackCapture(true)
+	if got := stackCount(Wrapf(Wrapf(traced, "1"), "2")); got != 3 {
+		t.Errorf("got: %d stack traces, want %d", got, 3)
+	}
+}
+
+// shallowError is an error with a given stack trace
+type shallowError struct {
+	error
+	st errors.StackTrace
+}
+
+func (s shallowError) Cause() error { return s.error }
+
+func (s shallowError) StackTrace() errors.StackTrace { return s.st }
+
+// Stack trimming logic tested:
+// Frames of the package, its tests included, are trimmed, however deep the helpers
+// (helpers outside the package are kept, see TestOrigin)
+// Traces without external frames are kept whole
+// Single frame and empty traces don't panic
+func TestGetStackTraceTrimming(t *testing.T) {
+	tests := []struct {
+		err   error
+		first string
+	}{
+		{Errorf("msg"), "testing.tRunner"},
+		{NotFound.Errorf("msg"), "testing.tRunner"},
+		{Wrapf(io.EOF, "msg"), "testing.tRunner"},
+		{New("msg").Wrap(io.EOF).Err(), "testing.tRunner"},
+	}
+	for _, tt := range tests {
+		got := strings.TrimSpace(GetStackTrace(tt.err))
+		if !strings.HasPrefix(got, tt.first+"\n") {
+			t.Errorf("got: %s, want it to start with %s", got, tt.first)
+		}
+	}
+
+	st := errors.WithStack(io.EOF).(stackTracer).StackTrace()
+	single := shallowError{io.EOF, st[:1]}
+	if got := strings.TrimSpace(GetStackTrace(single)); !strings.HasPrefix(got, "github.com/zgalor/weberr.TestGetStackTraceTrimming\n") {
+		t.Errorf("got: %q, want the single frame", got)
+	}
+	if got := GetStackTrace(shallowError{io.EOF, nil}); got != "" {
+		t.Errorf("got: %q, want an empty trace", got)
+	}
+}
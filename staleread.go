@@ -0,0 +1,56 @@
+package weberr
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StaleReadError reports a read that returned an older version of a resource
+// than the caller expected, typically from a replica lagging behind.
+// Unlike a Conflict, retrying the same request later is expected to succeed.
+type StaleReadError struct {
+	// Expected is the minimum version the caller expected
+	Expected uint64
+	// Found is the version that was read
+	Found uint64
+	// Lag is the estimated replication lag
+	Lag time.Duration
+}
+
+func (e *StaleReadError) Error() string {
+	return fmt.Sprintf("stale read: expected version %d, found %d (estimated lag %s)", e.Expected, e.Found, e.Lag)
+}
+
+// StaleRead creates a ServiceUnavailable error for a stale read, which
+// should be retried after the estimated replication lag.
+func StaleRead(expected, found uint64, lag time.Duration) error {
+	err := error(&StaleReadError{Expected: expected, Found: found, Lag: lag})
+	if captureStack(ServiceUnavailable) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:       err,
+		errorType:   ServiceUnavailable,
+		userMessage: "The requested data is not up to date yet, please retry",
+		retryAfter:  lag,
+	}
+}
+
+// GetStaleRead returns the StaleReadError of an error created by StaleRead, or nil.
+func GetStaleRead(err error) *StaleReadError {
+	var staleErr *StaleReadError
+	if stderrors.As(err, &staleErr) {
+		return staleErr
+	}
+
+	return nil
+}
+
+// IsStaleRead reports whether err was created by StaleRead.
+func IsStaleRead(err error) bool {
+	return GetStaleRead(err) != nil
+}
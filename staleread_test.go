@@ -0,0 +1,34 @@
+package weberr
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStaleRead(t *testing.T) {
+	err := Wrapf(StaleRead(5, 3, 200*time.Millisecond), "reading cluster")
+
+	if GetType(err) != ServiceUnavailable || GetRetryAfter(err) != 200*time.Millisecond || GetUserMessage(err) == "" {
+		t.Errorf("got: %v %v %q", GetType(err), GetRetryAfter(err), GetUserMessage(err))
+	}
+	if err.Error() != "reading cluster: stale read: expected version 5, found 3 (estimated lag 200ms)" {
+		t.Errorf("got: %q", err)
+	}
+
+	stale := GetStaleRead(err)
+	if !IsStaleRead(err) || stale.Expected != 5 || stale.Found != 3 || stale.Lag != 200*time.Millisecond {
+		t.Errorf("got: %+v", stale)
+	}
+
+	if GetStaleRead(fmt.Errorf("reading: %w", StaleRead(5, 3, 0))) == nil {
+		t.Errorf("expected the stale read of an error wrapped with %%w")
+	}
+
+	for _, err := range []error{nil, io.EOF, Conflict.Errorf("msg")} {
+		if IsStaleRead(err) {
+			t.Errorf("%v: expected no stale read", err)
+		}
+	}
+}
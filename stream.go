@@ -0,0 +1,129 @@
+package weberr
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+// maxCloseReason is the size limit of the reason of a WebSocket close
+// frame: control frames have at most 125 bytes, 2 of them hold the code
+const maxCloseReason = 123
+
+// SSEFrame returns err, transformed by the hooks, as a Server-Sent Events
+// frame of event type error, whose data is the JSON body of WriteError
+// using the package-level exposure policy:
+//
+//	event: error
+//	retry: 5000
+//	data: {"status":503,"message":"Please retry later"}
+//
+// The retry field is only set for errors with a retry delay, so that the
+// client reconnects when the service can take it.
+func SSEFrame(err error) []byte {
+	wr := Writer{Exposure: GetExposure()}
+	return wr.sseFrame(err)
+}
+
+// sseFrame renders err as a Server-Sent Events frame
+func (wr *Writer) sseFrame(err error) []byte {
+	err = Present(err)
+
+	data, encodeErr := json.Marshal(NewBody(err, wr.Exposure))
+	if encodeErr != nil {
+		// Details can hold anything, drop them rather than the whole body
+		body := NewBody(err, wr.Exposure)
+		body.Details = nil
+		data, _ = json.Marshal(body)
+	}
+
+	frame := []byte("event: error\n")
+	if d := GetRetryAfter(err); d > 0 {
+		frame = fmt.Appendf(frame, "retry: %d\n", d.Milliseconds())
+	}
+	frame = append(frame, "data: "...)
+	frame = append(frame, data...)
+
+	return append(frame, "\n\n"...)
+}
+
+// WriteSSE writes err to the event stream w as an error event (see SSEFrame),
+// flushing it if w is an http.Flusher. The stream can keep sending events.
+func (wr *Writer) WriteSSE(w io.Writer, err error) error {
+	if _, writeErr := w.Write(wr.sseFrame(err)); writeErr != nil {
+		return writeErr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return nil
+}
+
+// WriteSSE writes err to the event stream w using the package-level exposure policy.
+func WriteSSE(w io.Writer, err error) error {
+	wr := Writer{Exposure: GetExposure()}
+	return wr.WriteSSE(w, err)
+}
+
+// CloseCode returns the WebSocket close code (RFC 6455) matching the error type:
+//
+//   - 413 Request Entity Too Large is 1009 Message Too Big
+//   - 502 Bad Gateway is 1014 Bad Gateway
+//   - 503 Service Unavailable is 1013 Try Again Later
+//   - other server errors, and NoType, are 1011 Internal Error
+//   - other client errors are 4000 plus the status, e.g. 4404, in the range
+//     of the codes private to the application, as graphql-ws does
+func (errorType ErrorType) CloseCode() int {
+	switch status := errorType.HTTPStatus(); {
+	case status == http.StatusRequestEntityTooLarge:
+		return 1009
+	case status == http.StatusBadGateway:
+		return 1014
+	case status == http.StatusServiceUnavailable:
+		return 1013
+	case status >= 400 && status < 500:
+		return 4000 + status
+	}
+
+	return 1011
+}
+
+// CloseMessage returns err, transformed by the hooks, as the payload of a
+// WebSocket close frame: the close code of its type (see CloseCode) followed
+// by its user message, or the status text, as the reason. The reason is
+// truncated to the 123 bytes allowed, keeping it valid UTF-8. With
+// gorilla/websocket:
+//
+//	conn.WriteControl(websocket.CloseMessage, weberr.CloseMessage(err), deadline)
+func CloseMessage(err error) []byte {
+	code, reason := CloseFrame(err)
+
+	payload := binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(reason)), uint16(code))
+	return append(payload, reason...)
+}
+
+// CloseFrame returns the close code and reason of the WebSocket close frame
+// of err, as CloseMessage, for libraries closing connections with both, e.g.
+//
+//	code, reason := weberr.CloseFrame(err)
+//	conn.Close(websocket.StatusCode(code), reason)
+func CloseFrame(err error) (int, string) {
+	err = Present(err)
+	body := NewBody(err, ExposeUserMessageOnly)
+
+	reason := body.Message
+	if len(reason) > maxCloseReason {
+		// Cut before the rune overflowing the limit
+		cut := maxCloseReason
+		for cut > 0 && !utf8.RuneStart(reason[cut]) {
+			cut--
+		}
+		reason = reason[:cut]
+	}
+
+	return GetType(err).CloseCode(), reason
+}
@@ -0,0 +1,92 @@
+package weberr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSSEFrame(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{NotFound.UserErrorf("Not here"), "event: error\ndata: {\"status\":404,\"message\":\"Not here\"}\n\n"},
+		{Errorf("secret"), "event: error\ndata: {\"status\":500,\"message\":\"Internal Server Error\"}\n\n"},
+		{WithRetryAfter(ServiceUnavailable.UserErrorf("Later"), 5*time.Second), "event: error\nretry: 5000\ndata: {\"status\":503,\"message\":\"Later\"}\n\n"},
+	}
+	for _, tt := range tests {
+		if got := string(SSEFrame(tt.err)); got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+	}
+
+	// A message with a line break stays on the data line
+	if got := string(SSEFrame(BadRequest.UserErrorf("line\nbreak"))); strings.Count(got, "\n") != 3 {
+		t.Errorf("got: %q, want a single data line", got)
+	}
+}
+
+func TestWriteSSE(t *testing.T) {
+	rec := httptest.NewRecorder()
+	wr := Writer{Exposure: ExposeDetails}
+	if err := wr.WriteSSE(rec, AddDetails(BadRequest.UserErrorf("Bad"), "field")); err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Flushed {
+		t.Errorf("expected the event to be flushed")
+	}
+	if got, expected := rec.Body.String(), "event: error\ndata: {\"status\":400,\"message\":\"Bad\",\"details\":[\"field\"]}\n\n"; got != expected {
+		t.Errorf("got: %q, want %q", got, expected)
+	}
+}
+
+func TestCloseCode(t *testing.T) {
+	tests := []struct {
+		errorType ErrorType
+		expected  int
+	}{
+		{NoType, 1011},
+		{InternalServerError, 1011},
+		{GatewayTimeout, 1011},
+		{ServiceUnavailable, 1013},
+		{BadGateway, 1014},
+		{RequestEntityTooLarge, 1009},
+		{NotFound, 4404},
+		{Unauthorized, 4401},
+		{TooManyRequests, 4429},
+	}
+	for _, tt := range tests {
+		if got := tt.errorType.CloseCode(); got != tt.expected {
+			t.Errorf("%d: got: %d, want %d", tt.errorType, got, tt.expected)
+		}
+	}
+}
+
+func TestCloseMessage(t *testing.T) {
+	payload := CloseMessage(Forbidden.UserErrorf("Not yours"))
+	if code := binary.BigEndian.Uint16(payload); code != 4403 {
+		t.Errorf("got: %d, want 4403", code)
+	}
+	if reason := string(payload[2:]); reason != "Not yours" {
+		t.Errorf("got: %q, want %q", reason, "Not yours")
+	}
+
+	if code, reason := CloseFrame(Errorf("secret")); code != 1011 || reason != "Internal Server Error" {
+		t.Errorf("got: %d %q, want the status text", code, reason)
+	}
+
+	// Long reasons are cut at a rune boundary
+	long := strings.Repeat("é", 100)
+	code, reason := CloseFrame(BadRequest.UserErrorf("%s", long))
+	if code != 4400 || len(reason) > 123 || !utf8.ValidString(reason) || !strings.HasPrefix(long, reason) || len(reason) < 122 {
+		t.Errorf("got: %d, %d bytes, want 4400 and 122 bytes", code, len(reason))
+	}
+	if payload := CloseMessage(BadRequest.UserErrorf("%s", long)); len(payload) > 125 || !bytes.HasSuffix(payload, []byte(reason)) {
+		t.Errorf("got: %d bytes, want at most 125", len(payload))
+	}
+}
@@ -0,0 +1,110 @@
+package weberr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TemplateArg is a named argument of a user message template.
+type TemplateArg struct {
+	Name  string
+	Value interface{}
+}
+
+// Arg returns the argument name, with value, of a user message template.
+func Arg(name string, value interface{}) TemplateArg {
+	return TemplateArg{Name: name, Value: value}
+}
+
+// templated identifies an error with a user message template
+type templated interface {
+	UserMessageTemplate() (string, []TemplateArg)
+}
+
+// UserMessageTemplate returns the user message template and its arguments
+func (c *Error) UserMessageTemplate() (string, []TemplateArg) {
+	return c.messageTemplate, c.templateArgs
+}
+
+// GetUserMessageTemplate returns the template of the user message of err and
+// its arguments, so that the message can be rendered again, e.g. from a
+// translated template, or differently in an email:
+//
+//	template, args := weberr.GetUserMessageTemplate(err)
+//	html := weberr.RenderTemplate(emailTemplates[template], args...)
+//
+// It returns an empty template if the user message wasn't created by UserErrorT,
+// or was replaced since.
+func GetUserMessageTemplate(err error) (string, []TemplateArg) {
+	if t, ok := err.(templated); ok {
+		return t.UserMessageTemplate()
+	}
+
+	return "", nil
+}
+
+// RenderTemplate replaces the {name} placeholders of template with the
+// value of the argument name. Placeholders without argument are kept.
+func RenderTemplate(template string, args ...TemplateArg) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		placeholder, name := template[start:end+1], template[start+1:end]
+		template = template[end+1:]
+
+		found := false
+		for _, arg := range args {
+			if arg.Name == name {
+				fmt.Fprint(&b, arg.Value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			b.WriteString(placeholder)
+		}
+	}
+	b.WriteString(template)
+
+	return b.String()
+}
+
+// UserErrorT creates a new error of this type with a user message rendered
+// from template, whose {name} placeholders are replaced by the arguments:
+//
+//	weberr.TooManyRequests.UserErrorT("quota exceeded: {limit}", weberr.Arg("limit", 5))
+//
+// The template and arguments are kept, see GetUserMessageTemplate.
+func (errorType ErrorType) UserErrorT(template string, args ...TemplateArg) error {
+	message := RenderTemplate(template, args...)
+
+	err := plainErrorf("%s", message)
+	if captureStack(errorType) {
+		err = errors.WithStack(err)
+	}
+
+	return &Error{
+		error:           err,
+		errorType:       errorType,
+		userMessage:     message,
+		messageTemplate: template,
+		templateArgs:    args,
+	}
+}
+
+// UserErrorT creates a new error with a user message rendered from a template.
+func UserErrorT(template string, args ...TemplateArg) error {
+	return NoType.UserErrorT(template, args...)
+}
@@ -0,0 +1,69 @@
+package weberr
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		args     []TemplateArg
+		expected string
+	}{
+		{"", nil, ""},
+		{"quota exceeded: {limit}", []TemplateArg{Arg("limit", 5)}, "quota exceeded: 5"},
+		{"{a}{b} {a}", []TemplateArg{Arg("a", "x"), Arg("b", 1.5)}, "x1.5 x"},
+		{"missing {name}", nil, "missing {name}"},
+		{"unclosed {name", []TemplateArg{Arg("name", 1)}, "unclosed {name"},
+	}
+	for _, tt := range tests {
+		if got := RenderTemplate(tt.template, tt.args...); got != tt.expected {
+			t.Errorf("%q: got: %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+// Template logic tested:
+// UserErrorT renders the user message and keeps the template and arguments
+// Wrapping and setting attributes keep the template
+// Replacing the user message drops it
+func TestUserErrorT(t *testing.T) {
+	args := []TemplateArg{Arg("limit", 5)}
+	err := TooManyRequests.UserErrorT("quota exceeded: {limit}", args...)
+
+	if GetType(err) != TooManyRequests || GetUserMessage(err) != "quota exceeded: 5" || err.Error() != "quota exceeded: 5" {
+		t.Errorf("got: %v %q %q", GetType(err), GetUserMessage(err), err)
+	}
+
+	for _, err := range []error{
+		err,
+		Wrapf(err, "msg"),
+		AddDetails(err, "detail"),
+		BadRequest.Set(err),
+		SetCode(err, "A"),
+		WithRetryAfter(err, time.Second),
+		WithSeverity(err, SeverityWarning),
+		WithHeader(err, "X-Quota", "5"),
+		New("msg").Wrap(err).Err(),
+	} {
+		template, got := GetUserMessageTemplate(err)
+		if template != "quota exceeded: {limit}" || !reflect.DeepEqual(got, args) {
+			t.Errorf("%v: got: %q %v", err, template, got)
+		}
+	}
+
+	for _, err := range []error{
+		UserErrorf("msg"),
+		UserWrapf(err, "msg"),
+		SetUserMessage(err, "msg"),
+		New("msg").Wrap(err).User("msg").Err(),
+		io.EOF,
+	} {
+		if template, args := GetUserMessageTemplate(err); template != "" || args != nil {
+			t.Errorf("%v: got: %q %v, want no template", err, template, args)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package weberr
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"syscall"
+)
+
+// Timeoutf creates a new GatewayTimeout error with formatted string, for
+// operations that timed out waiting for another service.
+func Timeoutf(msg string, args ...interface{}) error {
+	return GatewayTimeout.Errorf(msg, args...)
+}
+
+// IsTimeout reports whether err, or an error it wraps, is a timeout:
+// a net.Error whose Timeout method returns true, context.DeadlineExceeded,
+// or an error of type GatewayTimeout or RequestTimeout.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	return hasType(err, GatewayTimeout, RequestTimeout)
+}
+
+// IsTransient reports whether err is likely to go away when the operation is
+// retried: timeouts (see IsTimeout), connections reset by the peer,
+// ServiceUnavailable errors, and errors with a retry delay (see
+// WithRetryAfter), found anywhere in the chain of err.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsTimeout(err) || stderrors.Is(err, syscall.ECONNRESET) || hasType(err, ServiceUnavailable) {
+		return true
+	}
+
+	for _, err := range Chain(err) {
+		if GetRetryAfter(err) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasType reports whether err or an error it wraps has one of types
+func hasType(err error, types ...ErrorType) bool {
+	for _, err := range Chain(err) {
+		for _, t := range types {
+			if GetType(err) == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
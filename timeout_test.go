@@ -0,0 +1,53 @@
+package weberr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTimeoutf(t *testing.T) {
+	err := Timeoutf("calling %s", "billing")
+	if GetType(err) != GatewayTimeout || err.Error() != "calling billing" || GetStackTrace(err) == "" {
+		t.Errorf("got: %v %q", GetType(err), err)
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	opErr := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	resetErr := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	tests := []struct {
+		err       error
+		timeout   bool
+		transient bool
+	}{
+		{nil, false, false},
+		{io.EOF, false, false},
+		{Wrapf(io.EOF, "msg"), false, false},
+		{NotFound.Errorf("msg"), false, false},
+		{Timeoutf("msg"), true, true},
+		{RequestTimeout.Errorf("msg"), true, true},
+		{Wrapf(opErr, "fetching"), true, true},
+		{fmt.Errorf("fetching: %w", context.DeadlineExceeded), true, true},
+		{BadRequest.Wrapf(context.DeadlineExceeded, "msg"), true, true},
+		{InternalServerError.Wrapf(Timeoutf("msg"), "msg"), true, true},
+		{Wrapf(resetErr, "fetching"), false, true},
+		{ServiceUnavailable.Errorf("msg"), false, true},
+		{Wrapf(WithRetryAfter(io.EOF, time.Second), "msg"), false, true},
+		{context.Canceled, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsTimeout(tt.err); got != tt.timeout {
+			t.Errorf("%v: got: timeout %v, want %v", tt.err, got, tt.timeout)
+		}
+		if got := IsTransient(tt.err); got != tt.transient {
+			t.Errorf("%v: got: transient %v, want %v", tt.err, got, tt.transient)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package weberr
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// wrapSite is where an error was wrapped, and what the wrapping added
+type wrapSite struct {
+	// pcs are the first callers of the wrapping function, enough to find
+	// the first one outside the package
+	pcs         [4]uintptr
+	n           int
+	message     string
+	userMessage string
+}
+
+// record records the callers of the function wrapping an error
+func (w *wrapSite) record() {
+	// Skip runtime.Callers, record and the wrapping function
+	w.n = runtime.Callers(3, w.pcs[:])
+}
+
+// callers returns the recorded callers
+func (w *wrapSite) callers() errors.StackTrace {
+	st := make(errors.StackTrace, w.n)
+	for i, pc := range w.pcs[:w.n] {
+		st[i] = errors.Frame(pc)
+	}
+
+	return st
+}
+
+// GetTrace returns a layered view of the error chain: a line for every
+// Wrapf and UserWrapf, outermost first, with where it happened and the
+// message it added, followed by the stack trace of the error
+// (as in GetStackTrace):
+//
+//	wrapped at cart.go:42 (cart.Load): loading cart
+//	wrapped at handler.go:17 (api.getCart): user message "Cart not found"
+//	EOF
+//	github.com/acme/orders.Get
+//		/src/orders/orders.go:12
+//	...
+//
+// Wrapping sites are not recorded for types whose stack capture is
+// disabled.
+func GetTrace(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, e := range Chain(err) {
+		c, ok := e.(*Error)
+		if !ok || c.wrapped == nil {
+			continue
+		}
+
+		st := trimStack(c.wrapped.callers())
+		if len(st) == 0 {
+			continue
+		}
+		fn, _ := frameFunction(st[0])
+		fmt.Fprintf(&b, "wrapped at %s:%d (%s)", st[0], st[0], fn[strings.LastIndex(fn, "/")+1:])
+
+		switch {
+		case c.wrapped.message != "":
+			fmt.Fprintf(&b, ": %s", c.wrapped.message)
+		case c.wrapped.userMessage != "":
+			fmt.Fprintf(&b, ": user message %q", c.wrapped.userMessage)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(Root(err).Error())
+	if hasStackTrace(err) {
+		b.WriteString(GetStackTrace(err))
+	}
+
+	return b.String()
+}
+
+// Origin returns where err was created: the file, line and full function
+// name of the first frame of its stack trace outside of the package, e.g.
+// for log lines. It returns zero values for errors without stack trace.
+func Origin(err error) (file string, line int, fn string) {
+	x, ok := baseStackTracer(err).(stackTracer)
+	if !ok {
+		return "", 0, ""
+	}
+
+	st := trimStack(x.StackTrace())
+	if len(st) == 0 {
+		return "", 0, ""
+	}
+
+	// Frames hold the return address of the calls
+	pc := uintptr(st[0]) - 1
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return "", 0, ""
+	}
+	file, line = f.FileLine(pc)
+
+	return file, line, f.Name()
+}
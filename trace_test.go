@@ -0,0 +1,131 @@
+package weberr_test
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+// loadCart wraps an error, as the layers of an application do
+func loadCart() error {
+	return weberr.Wrapf(readOrder(), "loading cart")
+}
+
+// readOrder creates an error wrapping io.EOF
+func readOrder() error {
+	return weberr.NotFound.Wrapf(io.EOF, "reading order %d", 42)
+}
+
+// Layered trace logic tested:
+// Every Wrapf and UserWrapf gets a line, outermost first, with its caller
+// The root error and its stack trace follow
+// Errors without stack trace nor wrapping sites only get their root error
+func TestGetTrace(t *testing.T) {
+	err := weberr.UserWrapf(loadCart(), "Cart not found")
+
+	lines := strings.Split(weberr.GetTrace(err), "\n")
+	expected := []string{
+		`^wrapped at trace_test.go:\d+ \(weberr_test.TestGetTrace\): user message "Cart not found"$`,
+		`^wrapped at trace_test.go:\d+ \(weberr_test.loadCart\): loading cart$`,
+		`^wrapped at trace_test.go:\d+ \(weberr_test.readOrder\): reading order 42$`,
+		`^EOF$`,
+		`^github.com/zgalor/weberr_test.readOrder$`,
+	}
+	if len(lines) < len(expected) {
+		t.Fatalf("got: %q, want at least %d lines", lines, len(expected))
+	}
+	for i, pattern := range expected {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Errorf("line %d: got: %q, want %s", i, lines[i], pattern)
+		}
+	}
+
+	built := weberr.New("building").Wrap(io.EOF).Err()
+	if got := weberr.GetTrace(built); !strings.HasPrefix(got, "wrapped at trace_test.go") || !strings.Contains(got, "(weberr_test.TestGetTrace): building\nEOF\n") {
+		t.Errorf("got: %q", got)
+	}
+
+	defer weberr.SetStackCapture(weberr.BadRequest, true)
+	weberr.SetStackCapture(weberr.BadRequest, false)
+	if got := weberr.GetTrace(weberr.Wrapf(weberr.BadRequest.Errorf("invalid"), "msg")); got != "invalid" {
+		t.Errorf("got: %q, want %q", got, "invalid")
+	}
+	if got := weberr.GetTrace(io.EOF); got != "EOF" {
+		t.Errorf("got: %q, want %q", got, "EOF")
+	}
+	if got := weberr.GetTrace(nil); got != "" {
+		t.Errorf("got: %q, want an empty trace", got)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	file, line, fn := weberr.Origin(weberr.Wrapf(loadCart(), "msg"))
+	if !strings.HasSuffix(file, "/trace_test.go") || line == 0 || fn != "github.com/zgalor/weberr_test.readOrder" {
+		t.Errorf("got: %q %d %q", file, line, fn)
+	}
+
+	for _, err := range []error{nil, io.EOF, weberr.NoType.ErrorfNoStack("msg")} {
+		if file, line, fn := weberr.Origin(err); file != "" || line != 0 || fn != "" {
+			t.Errorf("%v: got: %q %d %q, want no origin", err, file, line, fn)
+		}
+	}
+}
+
+// Stack filtering logic tested:
+// Frames of filtered prefixes are omitted
+// The depth limit applies after filtering
+// Resetting restores the full trace
+func TestStackFilter(t *testing.T) {
+	defer weberr.SetMaxStackDepth(0)
+	defer weberr.SetStackFrameFilter()
+
+	err := weberr.Errorf("msg")
+	full := strings.Count(weberr.GetStackTrace(err), "\n\t")
+
+	weberr.SetStackFrameFilter("testing.", "runtime.")
+	got := weberr.GetStackTrace(err)
+	if strings.Contains(got, "testing.tRunner") || strings.Contains(got, "runtime.goexit") ||
+		!strings.Contains(got, "weberr_test.TestStackFilter") {
+		t.Errorf("got: %s, want only the test frame", got)
+	}
+
+	weberr.SetStackFrameFilter()
+	weberr.SetMaxStackDepth(1)
+	if got := strings.Count(weberr.GetStackTrace(err), "\n\t"); got != 1 {
+		t.Errorf("got: %d frames, want 1", got)
+	}
+
+	weberr.SetMaxStackDepth(0)
+	if got := strings.Count(weberr.GetStackTrace(err), "\n\t"); got != full || full < 2 {
+		t.Errorf("got: %d frames, want %d", got, full)
+	}
+}
+
+func TestGetStackTraceJSON(t *testing.T) {
+	data, err := weberr.GetStackTraceJSON(weberr.Wrapf(weberr.Errorf("msg"), "wrapped"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frames []weberr.StackFrame
+	if err := json.Unmarshal(data, &frames); err != nil {
+		t.Fatalf("%s: %v", data, err)
+	}
+	if len(frames) < 2 || frames[0].Function != "github.com/zgalor/weberr_test.TestGetStackTraceJSON" ||
+		!strings.HasSuffix(frames[0].File, "/trace_test.go") || frames[0].Line == 0 {
+		t.Errorf("got: %s", data)
+	}
+	if got := len(weberr.GetStackFrames(weberr.Errorf("msg"))); got != len(frames) {
+		t.Errorf("got: %d frames, want %d", got, len(frames))
+	}
+
+	for _, err := range []error{nil, io.EOF, weberr.NoType.ErrorfNoStack("msg")} {
+		if data, _ := weberr.GetStackTraceJSON(err); string(data) != "[]" {
+			t.Errorf("%v: got: %s, want []", err, data)
+		}
+	}
+}
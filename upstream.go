@@ -0,0 +1,128 @@
+package weberr
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Upstream translates the error responses of a service behind a gateway into
+// errors of the gateway:
+//
+//	billing := &weberr.Upstream{Name: "billing"}
+//	...
+//	body, _ := io.ReadAll(resp.Body)
+//	if err := billing.FromResponse(resp, body); err != nil {
+//		weberr.WriteError(w, r, err)
+//		return
+//	}
+//
+// Client errors (4xx) keep their type, as the request of the client is at
+// fault. Server errors become BadGateway errors, except ServiceUnavailable
+// and GatewayTimeout errors that keep telling the client to retry later.
+type Upstream struct {
+	// Name names the service in the internal messages. Empty means
+	// using the host of the request.
+	Name string
+	// UserMessage returns the user message of the error translated from
+	// the upstream error, decoded from its body (the package JSON format,
+	// or application/problem+json) if possible. Nil, or an empty result,
+	// means keeping the upstream user message.
+	UserMessage func(upstream *Body) string
+}
+
+// UpstreamResponse is the detail of the errors translated by Upstream,
+// holding the response of the upstream service.
+type UpstreamResponse struct {
+	Service string `json:"service"`
+	Status  int    `json:"status"`
+	Body    string `json:"body,omitempty"`
+}
+
+// FromResponse returns the error of the gateway for the response of the
+// upstream service, whose body was read, or nil if the status isn't an
+// error status.
+// The error has the upstream code and user message, its details and an
+// UpstreamResponse detail, and its retry delay from the Retry-After header.
+func (u *Upstream) FromResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	service := u.Name
+	if service == "" && resp.Request != nil && resp.Request.URL != nil {
+		service = resp.Request.URL.Host
+	}
+
+	var errorType ErrorType
+	switch status := resp.StatusCode; {
+	case status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout:
+		errorType = ErrorType(status)
+	case status >= 500:
+		errorType = BadGateway
+	default:
+		errorType = ErrorType(status)
+	}
+
+	upstream, err := decodeResponseBody(resp.Header.Get("Content-Type"), body)
+	if err != nil {
+		upstream = &Body{Status: resp.StatusCode}
+	}
+	upstream.Status = resp.StatusCode
+
+	if len(body) > MaxBodySize {
+		body = body[:MaxBodySize]
+	}
+
+	msg := fmt.Sprintf("%s responded with status %d", service, resp.StatusCode)
+	if upstream.Message != "" {
+		msg += ": " + upstream.Message
+	}
+	err = plainErrorf("%s", msg)
+	if captureStack(errorType) {
+		err = errors.WithStack(err)
+	}
+
+	c := &Error{
+		error:       err,
+		errorType:   errorType,
+		userMessage: upstream.Message,
+		code:        upstream.Code,
+		retryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
+		details:     append(upstream.Details, UpstreamResponse{Service: service, Status: resp.StatusCode, Body: string(body)}),
+	}
+	if u.UserMessage != nil {
+		if msg := u.UserMessage(upstream); msg != "" {
+			c.userMessage = msg
+		}
+	}
+
+	return c
+}
+
+// FromUpstreamResponse returns the error of a gateway for the response of an
+// upstream service, whose body was read, as Upstream.FromResponse.
+func FromUpstreamResponse(resp *http.Response, body []byte) error {
+	return (&Upstream{}).FromResponse(resp, body)
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, in seconds or
+// as an HTTP date, or 0
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,110 @@
+package weberr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upstreamResponse returns a response of the billing service
+func upstreamResponse(status int, contentType, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	if contentType != "" {
+		rec.Header().Set("Content-Type", contentType)
+	}
+	rec.WriteHeader(status)
+	rec.WriteString(body)
+
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest("GET", "http://billing.internal/invoices/42", nil)
+	return resp
+}
+
+// Upstream translation logic tested:
+// Client errors keep their type, server errors become BadGateway, except 503 and 504
+// The code and user message of weberr and problem bodies are kept
+// The upstream response is a detail
+func TestFromUpstreamResponse(t *testing.T) {
+	tests := []struct {
+		status      int
+		contentType string
+		body        string
+		errorType   ErrorType
+		code        Code
+		userMessage string
+		message     string
+	}{
+		{404, "application/json", `{"status":404,"code":"billing.NOT_FOUND","message":"Invoice not found","details":["42"]}`,
+			NotFound, "billing.NOT_FOUND", "Invoice not found", "billing.internal responded with status 404: Invoice not found"},
+		{409, "application/problem+json", `{"type":"about:blank","title":"Conflict","status":409,"detail":"Already paid"}`,
+			Conflict, "", "Already paid", "billing.internal responded with status 409: Already paid"},
+		{500, "text/html", "<h1>oops</h1>", BadGateway, "", "", "billing.internal responded with status 500"},
+		{502, "", "", BadGateway, "", "", "billing.internal responded with status 502"},
+		{503, "", "", ServiceUnavailable, "", "", "billing.internal responded with status 503"},
+		{504, "", "", GatewayTimeout, "", "", "billing.internal responded with status 504"},
+	}
+	for _, tt := range tests {
+		err := FromUpstreamResponse(upstreamResponse(tt.status, tt.contentType, tt.body), []byte(tt.body))
+
+		if GetType(err) != tt.errorType || GetCode(err) != tt.code || GetUserMessage(err) != tt.userMessage || err.Error() != tt.message {
+			t.Errorf("%d: got: %v %q %q %q", tt.status, GetType(err), GetCode(err), GetUserMessage(err), err)
+		}
+
+		details := GetDetails(err)
+		last, ok := details[len(details)-1].(UpstreamResponse)
+		if !ok || last != (UpstreamResponse{Service: "billing.internal", Status: tt.status, Body: tt.body}) {
+			t.Errorf("%d: got: details %v", tt.status, details)
+		}
+	}
+
+	if err := FromUpstreamResponse(upstreamResponse(200, "", "ok"), []byte("ok")); err != nil {
+		t.Errorf("got: %v, want nil", err)
+	}
+}
+
+func TestUpstream(t *testing.T) {
+	billing := &Upstream{
+		Name: "billing",
+		UserMessage: func(upstream *Body) string {
+			if upstream.Status >= 500 {
+				return "Billing is unavailable"
+			}
+			return ""
+		},
+	}
+
+	resp := upstreamResponse(503, "", "")
+	resp.Header.Set("Retry-After", "30")
+	err := billing.FromResponse(resp, nil)
+	if GetUserMessage(err) != "Billing is unavailable" || GetRetryAfter(err) != 30*time.Second || !strings.HasPrefix(err.Error(), "billing ") {
+		t.Errorf("got: %q %v %q", GetUserMessage(err), GetRetryAfter(err), err)
+	}
+
+	body := `{"status":400,"message":"Bad invoice"}`
+	err = billing.FromResponse(upstreamResponse(400, "application/json", body), []byte(body))
+	if GetUserMessage(err) != "Bad invoice" {
+		t.Errorf("got: %q, want the upstream user message", GetUserMessage(err))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"", 0, 0},
+		{"120", 2 * time.Minute, 2 * time.Minute},
+		{"-1", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 59 * time.Minute, time.Hour},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header); got < tt.min || got > tt.max {
+			t.Errorf("%q: got: %v, want between %v and %v", tt.header, got, tt.min, tt.max)
+		}
+	}
+}
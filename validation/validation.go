@@ -0,0 +1,198 @@
+// Package validation generates catalog entries from the `validate` struct
+// tags of request types (as used by github.com/go-playground/validator), so
+// that validation errors have stable codes and consistent user messages that
+// can be translated like any other catalog entry.
+//
+// Codes have the form <prefix>.<Struct>.<field>.<rule>, where field is the
+// JSON name of the field when it has one:
+//
+//	type CreateOrder struct {
+//		Name string `json:"name" validate:"required,max=64"`
+//	}
+//
+//	func init() {
+//		validation.Register("orders", CreateOrder{})
+//	}
+//
+// registers "orders.CreateOrder.name.required" with the message "name is
+// required", and "orders.CreateOrder.name.max" with "name must be at most 64
+// characters long".
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zgalor/weberr"
+)
+
+// Entries returns the catalog entries for the validation rules of the struct
+// v, or of the struct v points to. Nested structs are scanned too, their
+// fields being named after their path, e.g. "address.city". The rules
+// following dive, which apply to the items of the field, are named after
+// the items, e.g. "tags[].required" for "each item of tags is required",
+// and the fields of struct items after their path, e.g. "items[].sku".
+func Entries(prefix string, v interface{}) []weberr.CatalogEntry {
+	t := deref(reflect.TypeOf(v))
+
+	return structEntries(prefix, t.Name(), "", t, map[reflect.Type]bool{})
+}
+
+// Register adds the entries of every struct to the default catalog.
+func Register(prefix string, structs ...interface{}) {
+	for _, v := range structs {
+		weberr.Register(Entries(prefix, v)...)
+	}
+}
+
+// Code returns the code of a rule of a struct field.
+func Code(prefix, structName, field, rule string) weberr.Code {
+	return weberr.Code(strings.Join([]string{prefix, structName, field, rule}, "."))
+}
+
+// FieldError returns a BadRequest error for a failed rule of a struct field,
+// with the user message of its catalog entry.
+func FieldError(prefix, structName, field, rule string) error {
+	err := weberr.BadRequest.UserErrorfKey(string(Code(prefix, structName, field, rule)))
+	return weberr.AddDetails(err, map[string]string{"field": field, "rule": rule})
+}
+
+// structEntries returns the entries of the fields of t, prefixing their names
+// with path. Structs on the path from the root, in onPath, are not scanned
+// again, so that recursive types such as trees end.
+func structEntries(prefix, structName, path string, t reflect.Type, onPath map[reflect.Type]bool) []weberr.CatalogEntry {
+	var entries []weberr.CatalogEntry
+
+	onPath[t] = true
+	defer delete(onPath, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := path + fieldName(field)
+
+		fieldType := deref(field.Type)
+
+		// Rules after dive apply to the elements, named "name[]", rules
+		// between keys and endkeys to the keys of maps, which are skipped
+		ruleName, ruleType, keys := name, fieldType, false
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			rule, param := splitRule(rule)
+			switch {
+			case rule == "dive":
+				ruleName += "[]"
+				if kind := ruleType.Kind(); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+					ruleType = deref(ruleType.Elem())
+				}
+				continue
+			case rule == "keys", rule == "endkeys":
+				keys = rule == "keys"
+				continue
+			case keys, rule == "", rule == "-", rule == "omitempty":
+				continue
+			}
+
+			entries = append(entries, weberr.CatalogEntry{
+				Code:    Code(prefix, structName, ruleName, rule),
+				Type:    weberr.BadRequest,
+				Message: escape(message(displayName(ruleName), rule, param, ruleType.Kind())),
+				Fields:  map[string]string{"field": "string", "rule": "string"},
+			})
+		}
+
+		// Structs are scanned, as are the struct items of a field with dive
+		if ruleType.Kind() == reflect.Struct && field.Tag.Get("validate") != "-" && !onPath[ruleType] {
+			entries = append(entries, structEntries(prefix, structName, ruleName+".", ruleType, onPath)...)
+		}
+	}
+
+	return entries
+}
+
+// deref returns the type pointers of t point to
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// displayName returns the name of a field in messages, e.g. "each item of
+// tags" for the elements "tags[]"
+func displayName(name string) string {
+	if strings.HasSuffix(name, "[]") {
+		return "each item of " + strings.TrimSuffix(name, "[]")
+	}
+
+	return name
+}
+
+// fieldName returns the JSON name of a field, or its Go name
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+// splitRule splits a rule such as "max=64" into its name and parameter
+func splitRule(rule string) (string, string) {
+	rule = strings.TrimSpace(rule)
+	if i := strings.Index(rule, "="); i >= 0 {
+		return rule[:i], rule[i+1:]
+	}
+
+	return rule, ""
+}
+
+// message returns the default user message of a rule
+func message(field, rule, param string, kind reflect.Kind) string {
+	unit := ""
+	switch kind {
+	case reflect.String:
+		unit = " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch rule {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "min":
+		return fmt.Sprintf("%s must be at least %s%s", field, param, unit)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", field, param, unit)
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s%s", field, param, unit)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, param)
+	case "lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.Join(strings.Fields(param), ", "))
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url", "uri":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "uuid", "uuid4":
+		return fmt.Sprintf("%s must be a valid UUID", field)
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+// escape escapes a message so that it can be used as a format string
+func escape(msg string) string {
+	return strings.ReplaceAll(msg, "%", "%%")
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type createOrder struct {
+	Name     string            `json:"name" validate:"required,max=64"`
+	Quantity int               `json:"quantity,omitempty" validate:"omitempty,gte=1,lte=100"`
+	Tags     []string          `validate:"max=10,dive,required,max=20"`
+	Color    string            `json:"color" validate:"oneof=red green"`
+	Discount string            `json:"discount" validate:"endswith=%"`
+	Address  *address          `json:"address" validate:"required"`
+	Labels   map[string]string `json:"-" validate:"dive,keys,min=2,endkeys,required"`
+	internal string            `validate:"required"`
+}
+
+func TestEntries(t *testing.T) {
+	expected := []struct {
+		code    weberr.Code
+		message string
+	}{
+		{"orders.createOrder.name.required", "name is required"},
+		{"orders.createOrder.name.max", "name must be at most 64 characters long"},
+		{"orders.createOrder.quantity.gte", "quantity must be greater than or equal to 1"},
+		{"orders.createOrder.quantity.lte", "quantity must be less than or equal to 100"},
+		{"orders.createOrder.Tags.max", "Tags must be at most 10 items"},
+		{"orders.createOrder.Tags[].required", "each item of Tags is required"},
+		{"orders.createOrder.Tags[].max", "each item of Tags must be at most 20 characters long"},
+		{"orders.createOrder.color.oneof", "color must be one of: red, green"},
+		{"orders.createOrder.discount.endswith", "discount is invalid"},
+		{"orders.createOrder.address.required", "address is required"},
+		{"orders.createOrder.address.city.required", "address.city is required"},
+		{"orders.createOrder.Labels[].required", "each item of Labels is required"},
+	}
+
+	got := Entries("orders", &createOrder{})
+	if len(got) != len(expected) {
+		t.Fatalf("got: %d entries %v, want %d", len(got), got, len(expected))
+	}
+	for i, entry := range got {
+		if entry.Code != expected[i].code || entry.Message != expected[i].message || entry.Type != weberr.BadRequest ||
+			entry.Fields["field"] != "string" || entry.Fields["rule"] != "string" {
+			t.Errorf("got: %q %q %v, want %q %q", entry.Code, entry.Message, entry.Type, expected[i].code, expected[i].message)
+		}
+	}
+
+	c := weberr.NewCatalog("en")
+	c.Add(got...)
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected a valid catalog, got %v", err)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	defer func(c *weberr.Catalog) { weberr.DefaultCatalog = c }(weberr.DefaultCatalog)
+	weberr.DefaultCatalog = weberr.NewCatalog("en")
+
+	Register("orders", createOrder{})
+
+	err := FieldError("orders", "createOrder", "name", "max")
+	if weberr.GetType(err) != weberr.BadRequest || weberr.GetCode(err) != "orders.createOrder.name.max" ||
+		weberr.GetUserMessage(err) != "name must be at most 64 characters long" {
+		t.Errorf("got: %v %q %q", weberr.GetType(err), weberr.GetCode(err), weberr.GetUserMessage(err))
+	}
+	if details := weberr.GetDetails(err); len(details) != 1 {
+		t.Errorf("got: %v, want one detail", details)
+	}
+}
+
+type node struct {
+	Name     string  `json:"name" validate:"required"`
+	Parent   *node   `json:"parent"`
+	Children []*node `json:"children" validate:"dive"`
+	Root     struct {
+		Node *node `json:"node"`
+	} `json:"root"`
+}
+
+func TestEntriesRecursive(t *testing.T) {
+	got := Entries("tree", node{})
+	if len(got) != 1 || got[0].Code != "tree.node.name.required" {
+		t.Errorf("got: %v, want the name entry only", got)
+	}
+}
+
+type orderItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gte=1"`
+}
+
+type bulkOrder struct {
+	Items   []orderItem           `json:"items" validate:"required,dive"`
+	ByID    map[string]*orderItem `json:"byId" validate:"dive"`
+	Skipped []orderItem           `json:"skipped"`
+}
+
+func TestEntriesDiveStructs(t *testing.T) {
+	expected := []weberr.Code{
+		"orders.bulkOrder.items.required",
+		"orders.bulkOrder.items[].sku.required",
+		"orders.bulkOrder.items[].quantity.gte",
+		"orders.bulkOrder.byId[].sku.required",
+		"orders.bulkOrder.byId[].quantity.gte",
+	}
+
+	got := Entries("orders", bulkOrder{})
+	if len(got) != len(expected) {
+		t.Fatalf("got: %d entries %v, want %d", len(got), got, len(expected))
+	}
+	for i, entry := range got {
+		if entry.Code != expected[i] {
+			t.Errorf("got: %q, want %q", entry.Code, expected[i])
+		}
+	}
+	if got[1].Message != "items[].sku is required" {
+		t.Errorf("got: %q", got[1].Message)
+	}
+}
@@ -0,0 +1,74 @@
+package weberrtest
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+// AssertType fails the test if err isn't of the expected type.
+func AssertType(t testing.TB, err error, expected weberr.ErrorType) {
+	t.Helper()
+
+	if got := weberr.GetType(err); got != expected {
+		t.Errorf("got error type %d, want %d (error: %v)", got, expected, err)
+	}
+}
+
+// AssertUserMessage fails the test if err doesn't have the expected user message.
+func AssertUserMessage(t testing.TB, err error, expected string) {
+	t.Helper()
+
+	if got := weberr.GetUserMessage(err); got != expected {
+		t.Errorf("got user message %q, want %q (error: %v)", got, expected, err)
+	}
+}
+
+// AssertCode fails the test if err doesn't have the expected code.
+func AssertCode(t testing.TB, err error, expected weberr.Code) {
+	t.Helper()
+
+	if got := weberr.GetCode(err); got != expected {
+		t.Errorf("got code %q, want %q (error: %v)", got, expected, err)
+	}
+}
+
+// AssertHTTPStatus fails the test if the recorded response status doesn't
+// match the type of err.
+func AssertHTTPStatus(t testing.TB, rec *httptest.ResponseRecorder, err error) {
+	t.Helper()
+
+	if expected := weberr.GetType(err).HTTPStatus(); rec.Code != expected {
+		t.Errorf("got status %d, want %d (error: %v)", rec.Code, expected, err)
+	}
+}
+
+// fileLine matches the file and line lines of a stack trace
+var fileLine = regexp.MustCompile(`(?m)^\t(.+):\d+$`)
+
+// NormalizeStackTrace makes a stack trace independent of line numbers and
+// of where the sources are, keeping only the base name of the files.
+func NormalizeStackTrace(trace string) string {
+	trace = fileLine.ReplaceAllStringFunc(trace, func(line string) string {
+		file := fileLine.FindStringSubmatch(line)[1]
+		return "\t" + filepath.Base(file)
+	})
+
+	return strings.TrimSpace(trace)
+}
+
+// AssertStackTrace fails the test if the stack trace of err doesn't match
+// the expected one, typically read from a golden file. Both are normalized
+// with NormalizeStackTrace, so that editing the code doesn't break the test.
+func AssertStackTrace(t testing.TB, err error, expected string) {
+	t.Helper()
+
+	got := NormalizeStackTrace(weberr.GetStackTrace(err))
+	if want := NormalizeStackTrace(expected); got != want {
+		t.Errorf("got stack trace:\n%s\nwant:\n%s", got, want)
+	}
+}
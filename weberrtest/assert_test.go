@@ -0,0 +1,76 @@
+package weberrtest
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+func TestAssertions(t *testing.T) {
+	err := weberr.SetCode(weberr.NotFound.UserErrorf("Not here"), "A")
+
+	rec := httptest.NewRecorder()
+	weberr.WriteError(rec, nil, err)
+
+	tests := []struct {
+		assert   func(t testing.TB)
+		failures int
+	}{
+		{func(t testing.TB) { AssertType(t, err, weberr.NotFound) }, 0},
+		{func(t testing.TB) { AssertType(t, err, weberr.BadRequest) }, 1},
+		{func(t testing.TB) { AssertType(t, io.EOF, weberr.NoType) }, 0},
+		{func(t testing.TB) { AssertUserMessage(t, err, "Not here") }, 0},
+		{func(t testing.TB) { AssertUserMessage(t, err, "") }, 1},
+		{func(t testing.TB) { AssertCode(t, err, "A") }, 0},
+		{func(t testing.TB) { AssertCode(t, err, "B") }, 1},
+		{func(t testing.TB) { AssertHTTPStatus(t, rec, err) }, 0},
+		{func(t testing.TB) { AssertHTTPStatus(t, rec, io.EOF) }, 1},
+	}
+	for i, tt := range tests {
+		r := &recorder{TB: t}
+		tt.assert(r)
+
+		if len(r.failures) != tt.failures {
+			t.Errorf("case %d: got: %q, want %d failures", i, r.failures, tt.failures)
+		}
+	}
+}
+
+func TestNormalizeStackTrace(t *testing.T) {
+	trace := "github.com/acme/svc.Get\n\t/home/me/src/svc/get.go:42\nruntime.goexit\n\t/usr/local/go/src/runtime/asm_amd64.s:1700\n"
+	expected := "github.com/acme/svc.Get\n\tget.go\nruntime.goexit\n\tasm_amd64.s"
+
+	if got := NormalizeStackTrace(trace); got != expected {
+		t.Errorf("got: %q, want %q", got, expected)
+	}
+}
+
+// newError creates an error, for a stable stack trace
+func newError() error {
+	return weberr.Errorf("boom")
+}
+
+func TestAssertStackTrace(t *testing.T) {
+	err := newError()
+	trace := weberr.GetStackTrace(err)
+	if !strings.Contains(trace, "weberrtest.newError") {
+		t.Fatalf("unexpected trace: %s", trace)
+	}
+
+	// Line numbers and directories differ from the actual trace
+	golden := strings.Replace(NormalizeStackTrace(trace), "assert_test.go", "/elsewhere/assert_test.go:1", -1)
+
+	r := &recorder{TB: t}
+	AssertStackTrace(r, err, golden)
+	if len(r.failures) != 0 {
+		t.Errorf("got: %q", r.failures)
+	}
+
+	AssertStackTrace(r, weberr.Errorf("elsewhere"), golden)
+	if len(r.failures) != 1 {
+		t.Errorf("got: %q, want one failure", r.failures)
+	}
+}
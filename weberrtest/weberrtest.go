@@ -0,0 +1,18 @@
+// Package weberrtest provides test helpers for code using weberr.
+package weberrtest
+
+import (
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+// AssertCatalogCoverage fails the test for every catalog entry missing a
+// translation in one of the catalog locales.
+func AssertCatalogCoverage(t testing.TB, c *weberr.Catalog) {
+	t.Helper()
+
+	for _, missing := range c.MissingTranslations() {
+		t.Errorf("%s: missing %q translation", missing.Code, missing.Locale)
+	}
+}
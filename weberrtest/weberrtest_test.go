@@ -0,0 +1,43 @@
+package weberrtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+// recorder records the failures reported by a helper
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCatalogCoverage(t *testing.T) {
+	c := weberr.NewCatalog("en", "fr", "de")
+	c.Add(weberr.CatalogEntry{
+		Code:         "A",
+		Type:         weberr.BadRequest,
+		Message:      "a",
+		Translations: map[string]string{"fr": "a", "de": "a"},
+	}, weberr.CatalogEntry{
+		Code:         "B",
+		Type:         weberr.BadRequest,
+		Message:      "b",
+		Translations: map[string]string{"fr": "b"},
+	})
+
+	r := &recorder{TB: t}
+	AssertCatalogCoverage(r, c)
+
+	expected := `B: missing "de" translation`
+	if len(r.failures) != 1 || r.failures[0] != expected {
+		t.Errorf("got: %q, want [%q]", r.failures, expected)
+	}
+}
@@ -0,0 +1,195 @@
+// Package wiretest holds the canonical wire format of weberr errors, as test
+// vectors shared with the services implementing it in other languages.
+//
+// The vectors are in vectors.json: each one describes an error (status,
+// code, user message, details, retry delay), the exposure it is rendered
+// with, the Accept header of the request, and the expected response headers
+// and body. Services in other languages can run their implementation against
+// the file, or serve the errors of the vectors and have them checked by
+// VerifyResponse:
+//
+//	for _, v := range wiretest.MustVectors() {
+//		req, _ := http.NewRequest("GET", pythonService+"/vectors/"+v.Name, nil)
+//		req.Header.Set("Accept", v.Accept)
+//		resp, _ := http.DefaultClient.Do(req)
+//		if err := v.VerifyResponse(resp); err != nil {
+//			t.Error(err)
+//		}
+//	}
+//
+// The JSON and application/problem+json formats have vectors, as the formats
+// other services decode. The XML and text formats are meant for people.
+package wiretest
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/zgalor/weberr"
+)
+
+//go:embed vectors.json
+var vectors []byte
+
+// Vector is a canonical error and its expected rendering.
+type Vector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Status is the HTTP status, and type, of the error
+	Status  int           `json:"status"`
+	Code    weberr.Code   `json:"code,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Details []interface{} `json:"details,omitempty"`
+	// RetryAfterMillis is the retry delay of the error
+	RetryAfterMillis int64 `json:"retryAfterMillis,omitempty"`
+	// Exposure is "user-message-only" or "details"
+	Exposure string `json:"exposure"`
+	// Accept is the Accept header of the request, empty meaning JSON
+	Accept string `json:"accept,omitempty"`
+
+	// Headers are headers the response must have
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the expected response body
+	Body json.RawMessage `json:"body"`
+}
+
+// Vectors returns the test vectors.
+func Vectors() ([]Vector, error) {
+	var v []Vector
+	if err := json.Unmarshal(vectors, &v); err != nil {
+		return nil, weberr.Wrapf(err, "decoding test vectors")
+	}
+
+	return v, nil
+}
+
+// MustVectors returns the test vectors, and panics if they can't be decoded.
+func MustVectors() []Vector {
+	v, err := Vectors()
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Err returns the error the vector describes.
+func (v Vector) Err() error {
+	err := weberr.ErrorType(v.Status).ErrorfNoStack("%s", v.Name)
+	if v.Message != "" {
+		err = weberr.SetUserMessage(err, v.Message)
+	}
+	if v.Code != "" {
+		err = weberr.SetCode(err, v.Code)
+	}
+	for _, d := range v.Details {
+		err = weberr.AddDetails(err, d)
+	}
+	if v.RetryAfterMillis > 0 {
+		err = weberr.WithRetryAfter(err, time.Duration(v.RetryAfterMillis)*time.Millisecond)
+	}
+
+	return err
+}
+
+// Writer returns a writer with the exposure of the vector.
+func (v Vector) Writer() *weberr.Writer {
+	wr := &weberr.Writer{Exposure: weberr.ExposeUserMessageOnly}
+	if v.Exposure == "details" {
+		wr.Exposure = weberr.ExposeDetails
+	}
+
+	return wr
+}
+
+// Request returns a request with the Accept header of the vector.
+func (v Vector) Request() *http.Request {
+	r, _ := http.NewRequest("GET", "/vectors/"+v.Name, nil)
+	if v.Accept != "" {
+		r.Header.Set("Accept", v.Accept)
+	}
+
+	return r
+}
+
+// Problem reports whether the body of the vector is an
+// application/problem+json body.
+func (v Vector) Problem() bool {
+	return v.Accept == "application/problem+json"
+}
+
+// VerifyBody checks that a response body is the expected one. Bodies are
+// compared as JSON values, so that formatting and key order don't matter.
+func (v Vector) VerifyBody(body []byte) error {
+	var got, expected interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return weberr.Wrapf(err, "%s: decoding body %q", v.Name, body)
+	}
+	if err := json.Unmarshal(v.Body, &expected); err != nil {
+		return weberr.Wrapf(err, "%s: decoding expected body", v.Name)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		return weberr.Errorf("%s: got body %s, want %s", v.Name, bytes.TrimSpace(body), v.Body)
+	}
+
+	return nil
+}
+
+// VerifyResponse checks that a response renders the error of the vector.
+// It reads the body of resp, but doesn't close it.
+func (v Vector) VerifyResponse(resp *http.Response) error {
+	if resp.StatusCode != v.Status {
+		return weberr.Errorf("%s: got status %d, want %d", v.Name, resp.StatusCode, v.Status)
+	}
+	for name, value := range v.Headers {
+		if got := resp.Header.Get(name); got != value {
+			return weberr.Errorf("%s: got %s header %q, want %q", v.Name, name, got, value)
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return weberr.Wrapf(err, "%s: reading body", v.Name)
+	}
+
+	return v.VerifyBody(body)
+}
+
+// VerifyError checks that an error decoded from the body of the vector,
+// e.g. by weberr.FromJSON, or weberr.FromResponse for problem bodies, has
+// its attributes.
+func (v Vector) VerifyError(err error) error {
+	if got := weberr.GetType(err); got != weberr.ErrorType(v.Status) {
+		return weberr.Errorf("%s: got type %d, want %d", v.Name, got, v.Status)
+	}
+	if got := weberr.GetCode(err); got != v.Code {
+		return weberr.Errorf("%s: got code %q, want %q", v.Name, got, v.Code)
+	}
+
+	var body struct {
+		weberr.Body
+		// Detail is the user message of problem bodies
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(v.Body, &body); err != nil {
+		return weberr.Wrapf(err, "%s: decoding expected body", v.Name)
+	}
+	if v.Problem() {
+		body.Message = body.Detail
+	}
+	if got := weberr.GetUserMessage(err); got != body.Message {
+		return weberr.Errorf("%s: got user message %q, want %q", v.Name, got, body.Message)
+	}
+	if got := weberr.GetDetails(err); !reflect.DeepEqual(got, body.Details) {
+		return weberr.Errorf("%s: got details %v, want %v", v.Name, got, body.Details)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package wiretest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zgalor/weberr"
+)
+
+func TestVectors(t *testing.T) {
+	vectors, err := Vectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range vectors {
+		rec := httptest.NewRecorder()
+		v.Writer().WriteError(rec, v.Request(), v.Err())
+
+		if err := v.VerifyResponse(rec.Result()); err != nil {
+			t.Error(err)
+		}
+		// Errors decoded from the body only carry the attributes it has
+		decoded := weberr.FromJSON(v.Body)
+		if v.Problem() {
+			decoded = weberr.FromResponse(&http.Response{
+				StatusCode: v.Status,
+				Header:     http.Header{"Content-Type": {v.Accept}},
+				Body:       io.NopCloser(bytes.NewReader(v.Body)),
+			})
+		}
+		if err := v.VerifyError(decoded); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestVerifyResponse(t *testing.T) {
+	v := MustVectors()[0]
+
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{v.Err(), true},
+		{weberr.SetCode(v.Err(), "other"), false},
+		{weberr.SetUserMessage(v.Err(), "other"), false},
+		{weberr.Conflict.Set(v.Err()), false},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		v.Writer().WriteError(rec, nil, tt.err)
+
+		if got := v.VerifyResponse(rec.Result()) == nil; got != tt.expected {
+			t.Errorf("%v: got: %v, want %v", tt.err, got, tt.expected)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(404)
+	rec.WriteString(string(v.Body))
+	if err := v.VerifyResponse(rec.Result()); err == nil {
+		t.Errorf("expected an error for the missing headers")
+	}
+}
@@ -0,0 +1,97 @@
+package weberr
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// inherit returns a new error wrapping err, with all its attributes.
+// Errors modifying an attribute of err are created by inherit, then
+// override the attribute, so that every attribute is kept.
+func inherit(err error) *Error {
+	c := &Error{error: err}
+	c.copyAttributes(err)
+
+	return c
+}
+
+// copyAttributes sets the attributes of c to those of err
+func (c *Error) copyAttributes(err error) {
+	c.errorType = GetType(err)
+	c.userMessage = GetUserMessage(err)
+	c.code = GetCode(err)
+	c.severity = getSeverity(err)
+	c.retryAfter = retryAfterHint(err)
+	c.headers = GetHeaders(err)
+	// Copy the details, as AddDetails appends to them
+	c.details = append([]interface{}(nil), GetDetails(err)...)
+	c.messageKey, c.messageArgs = getMessageKey(err)
+	c.messageTemplate, c.templateArgs = GetUserMessageTemplate(err)
+	c.id.Store(errorIDOf(err))
+}
+
+// WithType sets the type of an error, keeping its other attributes.
+// Unlike the methods of ErrorType, NoType is set as any other type.
+// It returns err itself if it already has the type.
+func WithType(err error, errorType ErrorType) error {
+	if err == nil {
+		return nil
+	}
+	if GetType(err) == errorType {
+		return err
+	}
+
+	c := inherit(err)
+	c.errorType = errorType
+	if needsStack(errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
+
+// WithUserMessage sets the user message of an error, replacing the one it
+// has, and keeping its other attributes, e.g. its type, whenever it was set:
+//
+//	err := weberr.NotFound.Wrapf(err, "loading order")
+//	...
+//	return weberr.WithUserMessage(err, "Order not found")
+func WithUserMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	c := inherit(err)
+	c.userMessage = msg
+	c.messageKey, c.messageArgs = "", nil
+	c.messageTemplate, c.templateArgs = "", nil
+	if needsStack(c.errorType, err) {
+		c.error = errors.WithStack(err)
+	}
+
+	return c
+}
+
+// WithUserMessagef sets a formatted user message on an error, as WithUserMessage.
+func WithUserMessagef(err error, msg string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return WithUserMessage(err, fmt.Sprintf(msg, args...))
+}
+
+// WithStack adds a stack trace to an error without one, even if stack
+// traces are disabled for its type (see SetStackCapture), keeping its
+// attributes. It returns err itself if it already has a stack trace.
+func WithStack(err error) error {
+	if err == nil || hasStackTrace(err) {
+		return err
+	}
+
+	c := inherit(err)
+	c.error = errors.WithStack(err)
+
+	return c
+}
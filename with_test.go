@@ -0,0 +1,132 @@
+package weberr
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Modifier logic tested:
+// Each modifier sets its attribute and keeps the others
+// Modifiers that wouldn't change the error return it
+func TestWithType(t *testing.T) {
+	base := WithRetryAfter(SetCode(NotFound.UserWrapf(io.EOF, "Not here"), "A"), time.Second)
+
+	err := WithType(base, Conflict)
+	if GetType(err) != Conflict || GetUserMessage(err) != "Not here" || GetCode(err) != "A" ||
+		GetRetryAfter(err) != time.Second || err.Error() != "EOF" {
+		t.Errorf("got: %v %q %q %v %q", GetType(err), GetUserMessage(err), GetCode(err), GetRetryAfter(err), err)
+	}
+	if GetType(base) != NotFound {
+		t.Errorf("got: %v, want the original error unchanged", GetType(base))
+	}
+
+	if got := GetType(WithType(base, NoType)); got != NoType {
+		t.Errorf("got: %v, want %v", got, NoType)
+	}
+	if WithType(base, NotFound) != base {
+		t.Errorf("expected the error itself when it has the type")
+	}
+	if WithType(nil, NotFound) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestWithUserMessage(t *testing.T) {
+	base := AddDetails(NotFound.Wrapf(io.EOF, "loading order"), "detail")
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{WithUserMessage(base, "Order not found"), "Order not found"},
+		{WithUserMessagef(base, "Order %d not found", 42), "Order 42 not found"},
+		{WithUserMessage(UserWrapf(base, "Not here"), "Order not found"), "Order not found"},
+		{WithUserMessage(UserErrorfKey("orders.NOT_FOUND"), "Order not found"), "Order not found"},
+	}
+	for _, tt := range tests {
+		if got := GetUserMessage(tt.err); got != tt.expected {
+			t.Errorf("got: %q, want %q", got, tt.expected)
+		}
+		if key, _ := getMessageKey(tt.err); key != "" {
+			t.Errorf("got: key %q, want none", key)
+		}
+	}
+
+	err := tests[0].err
+	if GetType(err) != NotFound || !compare(GetDetails(err), []interface{}{"detail"}) || err.Error() != "loading order: EOF" {
+		t.Errorf("got: %v %v %q", GetType(err), GetDetails(err), err)
+	}
+	if WithUserMessage(nil, "msg") != nil || WithUserMessagef(nil, "msg") != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	defer SetStackCapture(NotFound, true)
+	SetStackCapture(NotFound, false)
+
+	err := WithStack(SetCode(NotFound.ErrorfNoStack("msg"), "A"))
+	// Stack traces start at the first frame outside the package
+	if !strings.HasPrefix(GetStackTrace(err), "\ntesting.tRunner\n") || GetType(err) != NotFound || GetCode(err) != "A" {
+		t.Errorf("got: %v %q %q", GetType(err), GetCode(err), GetStackTrace(err))
+	}
+	if !strings.HasPrefix(GetStackTrace(WithStack(io.EOF)), "\ntesting.tRunner\n") {
+		t.Errorf("got: %q, want a stack trace", GetStackTrace(WithStack(io.EOF)))
+	}
+
+	stacked := Errorf("msg")
+	if WithStack(stacked) != stacked || WithStack(nil) != nil {
+		t.Errorf("expected the error itself when it has a stack trace")
+	}
+}
+
+// Every error created from another one has all the attributes it doesn't set
+func TestAttributesKept(t *testing.T) {
+	base := WithHeader(WithSeverity(WithRetryAfter(AddDetails(SetCode(
+		NotFound.UserErrorT("Order {id} not found", Arg("id", 42)), "A"), "detail"), time.Second), SeverityWarning), "X-A", "1")
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"Wrapf", Wrapf(base, "msg")},
+		{"ErrorType.Wrapf", NotFound.Wrapf(base, "msg")},
+		{"Set", NotFound.Set(base)},
+		{"SetCode", SetCode(base, "A")},
+		{"AddDetails", AddDetails(base, "other")},
+		{"WithSeverity", WithSeverity(base, SeverityWarning)},
+		{"WithRetryAfter", WithRetryAfter(base, time.Second)},
+		{"WithHeader", WithHeader(base, "X-B", "2")},
+		{"WithType", WithType(base, NotFound)},
+		{"WithStack", WithStack(base)},
+		{"Builder", New("msg").Wrap(base).Err()},
+	}
+	for _, tt := range tests {
+		template, args := GetUserMessageTemplate(tt.err)
+		if GetType(tt.err) != NotFound || GetUserMessage(tt.err) != "Order 42 not found" || GetCode(tt.err) != "A" ||
+			GetDetails(tt.err)[0] != "detail" || GetRetryAfter(tt.err) != time.Second || GetSeverity(tt.err) != SeverityWarning ||
+			GetHeaders(tt.err).Get("X-A") != "1" || template != "Order {id} not found" || len(args) != 1 {
+			t.Errorf("%s: got: %v %q %q %v %v %v %v %q %v", tt.name, GetType(tt.err), GetUserMessage(tt.err), GetCode(tt.err),
+				GetDetails(tt.err), GetRetryAfter(tt.err), GetSeverity(tt.err), GetHeaders(tt.err), template, args)
+		}
+	}
+
+	// Errors created from the same one don't share their details
+	detailed := AddDetails(AddDetails(base, "b"), "c")
+	first, second := AddDetails(detailed, "first"), AddDetails(detailed, "second")
+	if got := GetDetails(first); len(got) != 4 || got[3] != "first" {
+		t.Errorf("got: %v, want the details of the first sibling", got)
+	}
+	if got := GetDetails(second); len(got) != 4 || got[3] != "second" {
+		t.Errorf("got: %v, want the details of the second sibling", got)
+	}
+
+	// Changing the user message drops its template
+	for _, err := range []error{UserWrapf(base, "Sorry"), SetUserMessage(base, "Sorry"), WithUserMessage(base, "Sorry")} {
+		if template, _ := GetUserMessageTemplate(err); template != "" || GetCode(err) != "A" || GetRetryAfter(err) != time.Second {
+			t.Errorf("got: %q %q %v, want no template", template, GetCode(err), GetRetryAfter(err))
+		}
+	}
+}